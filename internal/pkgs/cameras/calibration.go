@@ -0,0 +1,132 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Inverting the distortion carries on until points move less than
+// undistort_tolerance, so it's as exact as the distortion allows. OpenCV's
+// undistortPoints stops after 5 iterations by default, which leaves points
+// near the edges of strongly distorted images short of where they belong.
+const (
+	undistort_tolerance      float64 = 1e-12
+	undistort_max_iterations int     = 100 // Only reached if it never settles
+)
+
+// Intrinsics are the pinhole parameters of a calibrated camera, in pixels,
+// as reported by OpenCV. Pixel centres are at integer coordinates and y
+// points down the image.
+type Intrinsics struct {
+	Fx float64
+	Fy float64
+	Cx float64
+	Cy float64
+}
+
+func IntrinsicsFromFov(vfov float64, width int, height int) Intrinsics {
+	// Square pixels with the principal point in the middle of the image.
+	f := float64(height) / 2.0 / math.Tan(vfov*math.Pi/360.0)
+	return Intrinsics{
+		Fx: f,
+		Fy: f,
+		Cx: float64(width-1) / 2.0,
+		Cy: float64(height-1) / 2.0,
+	}
+}
+
+func (in Intrinsics) normalise(x float64, y float64) (float64, float64) {
+	// Continuous pixel coordinates to normalised image coordinates.
+	return (x - 0.5 - in.Cx) / in.Fx, (y - 0.5 - in.Cy) / in.Fy
+}
+
+// BrownConrady is OpenCV's radial (K1..K3) and tangential (P1, P2) lens
+// distortion model, acting on normalised image coordinates.
+type BrownConrady struct {
+	K1 float64
+	K2 float64
+	K3 float64
+	P1 float64
+	P2 float64
+}
+
+func (d BrownConrady) Distort(x float64, y float64) (float64, float64) {
+	// Where a point at ideal normalised coordinates x, y appears through the lens.
+	r2 := x*x + y*y
+	radial := 1.0 + ((d.K3*r2+d.K2)*r2+d.K1)*r2
+	dx := 2.0*d.P1*x*y + d.P2*(r2+2.0*x*x)
+	dy := d.P1*(r2+2.0*y*y) + 2.0*d.P2*x*y
+	return x*radial + dx, y*radial + dy
+}
+
+func (d BrownConrady) Undistort(xd float64, yd float64) (float64, float64) {
+	// The inverse of Distort, which has no closed form.
+	x, y := xd, yd
+	for i := 0; i < undistort_max_iterations; i++ {
+		r2 := x*x + y*y
+		radial := 1.0 + ((d.K3*r2+d.K2)*r2+d.K1)*r2
+		dx := 2.0*d.P1*x*y + d.P2*(r2+2.0*x*x)
+		dy := d.P1*(r2+2.0*y*y) + 2.0*d.P2*x*y
+		next_x, next_y := (xd-dx)/radial, (yd-dy)/radial
+		settled := math.Abs(next_x-x) <= undistort_tolerance && math.Abs(next_y-y) <= undistort_tolerance
+		x, y = next_x, next_y
+		if settled {
+			break
+		}
+	}
+	return x, y
+}
+
+func MakeCalibrated(look_from vectors.Vector, look_at vectors.Vector, up vectors.Vector, intrinsics Intrinsics, distortion BrownConrady, width int, height int) Calibrated {
+	if width <= 0 || height <= 0 {
+		log.Fatal("Width and height must be positive.")
+	}
+	if intrinsics.Fx <= 0.0 || intrinsics.Fy <= 0.0 {
+		log.Fatal("Focal lengths must be positive.")
+	}
+	right, true_up, forward := viewBasis(&look_from, &look_at, &up)
+
+	return Calibrated{
+		LookFrom:   look_from,
+		LookAt:     look_at,
+		Up:         up,
+		Intrinsics: intrinsics,
+		Distortion: distortion,
+		Width:      width,
+		Height:     height,
+		right:      right,
+		up:         true_up,
+		forward:    forward,
+	}
+}
+
+// Calibrated is a pinhole camera with a real lens's distortion, so that a
+// render lines up pixel for pixel with footage from the calibrated camera.
+type Calibrated struct {
+	LookFrom   vectors.Vector
+	LookAt     vectors.Vector
+	Up         vectors.Vector
+	Intrinsics Intrinsics
+	Distortion BrownConrady
+	Width      int
+	Height     int
+
+	right   *vectors.Vector
+	up      *vectors.Vector
+	forward *vectors.Vector
+}
+
+func (c Calibrated) Resolution() (int, int) {
+	return c.Width, c.Height
+}
+
+func (c Calibrated) Ray(x float64, y float64) rays.Ray {
+	xd, yd := c.Intrinsics.normalise(x, y)
+	ix, iy := c.Distortion.Undistort(xd, yd)
+	direction := c.forward.Add(c.right.MultiplyScalar(ix)).Add(c.up.MultiplyScalar(-iy))
+	origin := c.LookFrom
+	return rays.MakeRay(&origin, direction)
+}
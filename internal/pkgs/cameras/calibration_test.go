@@ -0,0 +1,64 @@
+package cameras
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestBrownConrady_Undistort(t *testing.T) {
+	tests := []struct {
+		name       string
+		distortion BrownConrady
+	}{
+		{name: "No distortion", distortion: BrownConrady{}},
+		{name: "Barrel", distortion: BrownConrady{K1: -0.28, K2: 0.07, K3: -0.01}},
+		{name: "Pincushion with tangential", distortion: BrownConrady{K1: 0.1, K2: 0.01, P1: 0.001, P2: -0.0005}},
+	}
+	points := [][2]float64{{0.0, 0.0}, {0.3, -0.2}, {-0.5, 0.4}, {0.6, 0.6}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range points {
+				xd, yd := tt.distortion.Distort(p[0], p[1])
+				if x, y := tt.distortion.Undistort(xd, yd); !utils.Close_enough(x, p[0]) || !utils.Close_enough(y, p[1]) {
+					t.Errorf("Undistort(Distort(%v)) = (%v, %v)", p, x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestBrownConrady_Undistort_edges(t *testing.T) {
+	// Out in the corners of a wide lens, a few iterations aren't enough to
+	// get back where the point started
+	distortion := BrownConrady{K1: -0.28, K2: 0.07, K3: -0.01, P1: 0.001, P2: -0.0005}
+	for _, p := range [][2]float64{{0.9, 0.7}, {-1.0, 0.75}, {-0.8, -0.6}} {
+		xd, yd := distortion.Distort(p[0], p[1])
+		if x, y := distortion.Undistort(xd, yd); math.Abs(x-p[0]) > 1e-9 || math.Abs(y-p[1]) > 1e-9 {
+			t.Errorf("Undistort(Distort(%v)) = (%v, %v)", p, x, y)
+		}
+	}
+}
+
+func TestCalibrated_Ray(t *testing.T) {
+	look_from := vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}
+	look_at := vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+
+	// Without distortion it's the perspective camera with the same field of view
+	p := MakePerspective(look_from, look_at, up, 50.0, 64, 48)
+	c := MakeCalibrated(look_from, look_at, up, IntrinsicsFromFov(50.0, 64, 48), BrownConrady{}, 64, 48)
+	for _, xy := range [][2]float64{{32.0, 24.0}, {0.5, 0.5}, {63.5, 10.5}, {5.0, 47.0}} {
+		if got, want := c.Ray(xy[0], xy[1]), p.Ray(xy[0], xy[1]); !got.CloseTo(want) {
+			t.Errorf("Calibrated.Ray(%v) = %s, want %s", xy, got.Print(), want.Print())
+		}
+	}
+
+	// Barrel distortion squeezes the edges in, so an edge pixel sees further out than a pinhole would
+	barrel := MakeCalibrated(look_from, look_at, up, IntrinsicsFromFov(50.0, 64, 48), BrownConrady{K1: -0.3}, 64, 48)
+	if got, pinhole := barrel.Ray(0.5, 24.0), p.Ray(0.5, 24.0); !(got.Direction.Z < pinhole.Direction.Z) {
+		t.Errorf("Barrel distorted edge ray %s should be further from the axis than %s", got.Print(), pinhole.Print())
+	}
+}
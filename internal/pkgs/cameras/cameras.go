@@ -0,0 +1,64 @@
+package cameras
+
+import (
+	"log"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+type Camera interface {
+	Resolution() (int, int)            // Output width and height in pixels
+	Ray(x float64, y float64) rays.Ray // Primary ray through continuous pixel coordinates, (0, 0) is the top left corner
+}
+
+// Cameras that need several rays per pixel, such as ThinLens, also implement
+// Sampler. Ray still gives a single representative ray for those.
+type Sampler interface {
+	Samples() int
+	SampleRay(x float64, y float64, sample int) rays.Ray
+}
+
+func Samples(c Camera) int {
+	// Rays needed per pixel.
+	if sampler, ok := c.(Sampler); ok {
+		return sampler.Samples()
+	}
+	return 1
+}
+
+func RayGenerator(c Camera) rays.Generator {
+	// Rays through the centre of each pixel, one per sample.
+	if sampler, ok := c.(Sampler); ok {
+		return func(x int, y int, sample int) rays.Ray {
+			return sampler.SampleRay(float64(x)+0.5, float64(y)+0.5, sample)
+		}
+	}
+	return func(x int, y int, sample int) rays.Ray {
+		return c.Ray(float64(x)+0.5, float64(y)+0.5)
+	}
+}
+
+// viewBasis returns the right, up and forward unit vectors of a camera at
+// look_from pointing at look_at. The scene is left handed: with the default
+// up of +Y and looking down +Z, right is +X.
+func viewBasis(look_from *vectors.Vector, look_at *vectors.Vector, up *vectors.Vector) (right *vectors.Vector, true_up *vectors.Vector, forward *vectors.Vector) {
+	forward = look_at.Subtract(look_from)
+	if forward.Magnitude() == 0.0 {
+		log.Fatal("Look-from and look-at points must be different.")
+	}
+	forward.Normalise()
+	right = up.Cross(forward)
+	if right.Magnitude() == 0.0 {
+		log.Fatal("Up vector must not be parallel to the viewing direction.")
+	}
+	right.Normalise()
+	true_up = forward.Cross(right)
+	return
+}
+
+// screenCoords maps continuous pixel coordinates onto [-1, 1] in both
+// directions, with +1 at the right and top edges of the image.
+func screenCoords(x float64, y float64, width int, height int) (float64, float64) {
+	return 2.0*x/float64(width) - 1.0, 1.0 - 2.0*y/float64(height)
+}
@@ -0,0 +1,110 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+type FisheyeProjection int
+
+const (
+	Equidistant FisheyeProjection = iota // r = f * theta
+	Equisolid                            // r = 2 * f * sin(theta / 2)
+)
+
+func FisheyeIntrinsics(projection FisheyeProjection, fov float64, width int, height int) Intrinsics {
+	// Intrinsics for a circular fisheye whose image circle fits the image and spans fov degrees.
+	radius := float64(minInt(width, height)) / 2.0
+	half_angle := fov * math.Pi / 360.0
+	var f float64
+	switch projection {
+	case Equidistant:
+		f = radius / half_angle
+	case Equisolid:
+		f = radius / (2.0 * math.Sin(half_angle/2.0))
+	}
+	return Intrinsics{
+		Fx: f,
+		Fy: f,
+		Cx: float64(width-1) / 2.0,
+		Cy: float64(height-1) / 2.0,
+	}
+}
+
+func MakeFisheye(look_from vectors.Vector, look_at vectors.Vector, up vectors.Vector, projection FisheyeProjection, intrinsics Intrinsics, width int, height int) Fisheye {
+	if width <= 0 || height <= 0 {
+		log.Fatal("Width and height must be positive.")
+	}
+	if intrinsics.Fx <= 0.0 || intrinsics.Fy <= 0.0 {
+		log.Fatal("Focal lengths must be positive.")
+	}
+	right, true_up, forward := viewBasis(&look_from, &look_at, &up)
+
+	return Fisheye{
+		LookFrom:   look_from,
+		LookAt:     look_at,
+		Up:         up,
+		Projection: projection,
+		Intrinsics: intrinsics,
+		Width:      width,
+		Height:     height,
+		right:      right,
+		up:         true_up,
+		forward:    forward,
+	}
+}
+
+// Fisheye maps the distance from the principal point to the angle from the
+// view direction, rather than its tangent, so it can see 180 degrees and
+// more. Outside the image circle the projection carries on until it points
+// straight backwards.
+type Fisheye struct {
+	LookFrom   vectors.Vector
+	LookAt     vectors.Vector
+	Up         vectors.Vector
+	Projection FisheyeProjection
+	Intrinsics Intrinsics
+	Width      int
+	Height     int
+
+	right   *vectors.Vector
+	up      *vectors.Vector
+	forward *vectors.Vector
+}
+
+func (c Fisheye) Resolution() (int, int) {
+	return c.Width, c.Height
+}
+
+func (c Fisheye) Ray(x float64, y float64) rays.Ray {
+	mx, my := c.Intrinsics.normalise(x, y)
+	r := math.Sqrt(mx*mx + my*my)
+	origin := c.LookFrom
+	if r == 0.0 {
+		direction := *c.forward
+		return rays.MakeRay(&origin, &direction)
+	}
+
+	var theta float64
+	switch c.Projection {
+	case Equidistant:
+		theta = r
+	case Equisolid:
+		theta = 2.0 * math.Asin(math.Min(r/2.0, 1.0))
+	}
+	theta = math.Min(theta, math.Pi)
+
+	sideways := c.right.MultiplyScalar(mx / r).Add(c.up.MultiplyScalar(-my / r))
+	direction := c.forward.MultiplyScalar(math.Cos(theta)).Add(sideways.MultiplyScalar(math.Sin(theta)))
+	return rays.MakeRay(&origin, direction)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+package cameras
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestFisheye_Ray(t *testing.T) {
+	tests := []struct {
+		name       string
+		projection FisheyeProjection
+	}{
+		{name: "Equidistant", projection: Equidistant},
+		{name: "Equisolid", projection: Equisolid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := MakeFisheye(
+				vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+				vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+				vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+				tt.projection,
+				FisheyeIntrinsics(tt.projection, 180.0, 200, 100),
+				200,
+				100,
+			)
+			centre := c.Ray(100.0, 50.0)
+			if !centre.Direction.CloseTo(&vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}) {
+				t.Errorf("Centre ray = %s, want straight ahead", centre.Print())
+			}
+			// The image circle touches the top and bottom of the image at 90 degrees
+			top := c.Ray(100.0, 0.0)
+			if !top.Direction.CloseTo(&vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}) {
+				t.Errorf("Top ray = %s, want straight up", top.Print())
+			}
+			side := c.Ray(50.0, 50.0)
+			if !side.Direction.CloseTo(&vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0}) {
+				t.Errorf("Side ray = %s, want straight left", side.Print())
+			}
+			// Far outside the circle the rays never turn past straight backwards
+			corner := c.Ray(0.0, 0.0)
+			if corner.Direction.Z < -1.0 || math.IsNaN(corner.Direction.Z) {
+				t.Errorf("Corner ray = %s", corner.Print())
+			}
+		})
+	}
+}
+
+func TestFisheye_Projections(t *testing.T) {
+	// Halfway to the image circle is 45 degrees for equidistant, less for equisolid
+	look_from := vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}
+	look_at := vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+	equidistant := MakeFisheye(look_from, look_at, up, Equidistant, FisheyeIntrinsics(Equidistant, 180.0, 100, 100), 100, 100)
+	equisolid := MakeFisheye(look_from, look_at, up, Equisolid, FisheyeIntrinsics(Equisolid, 180.0, 100, 100), 100, 100)
+
+	if got := math.Acos(equidistant.Ray(75.0, 50.0).Direction.Z); !utils.Close_enough(got, math.Pi/4.0) {
+		t.Errorf("Equidistant angle = %v, want %v", got, math.Pi/4.0)
+	}
+	want := 2.0 * math.Asin(math.Sin(math.Pi/4.0)/2.0)
+	if got := math.Acos(equisolid.Ray(75.0, 50.0).Direction.Z); !utils.Close_enough(got, want) {
+		t.Errorf("Equisolid angle = %v, want %v", got, want)
+	}
+}
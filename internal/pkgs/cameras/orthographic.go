@@ -0,0 +1,94 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Elevation angles, in degrees, of the common axonometric views.
+// True isometric has all three axes equally foreshortened, the dimetric
+// angle is the 2:1 "pixel art" one where lines along X and Z rise one pixel
+// for every two across. Seen from 45 degrees round, those lines slope by
+// the sine of the elevation, so that's 30 degrees.
+var isometric_elevation float64 = math.Atan(1.0/math.Sqrt2) * 180.0 / math.Pi
+var dimetric_elevation float64 = math.Asin(0.5) * 180.0 / math.Pi
+
+func MakeOrthographic(look_from vectors.Vector, look_at vectors.Vector, up vectors.Vector, view_height float64, width int, height int) Orthographic {
+	if width <= 0 || height <= 0 {
+		log.Fatal("Width and height must be positive.")
+	}
+	if view_height <= 0.0 {
+		log.Fatal("View height must be positive.")
+	}
+	right, true_up, forward := viewBasis(&look_from, &look_at, &up)
+	view_width := view_height * float64(width) / float64(height)
+
+	return Orthographic{
+		LookFrom:   look_from,
+		LookAt:     look_at,
+		Up:         up,
+		ViewHeight: view_height,
+		Width:      width,
+		Height:     height,
+		horizontal: right.MultiplyScalar(view_width / 2.0),
+		vertical:   true_up.MultiplyScalar(view_height / 2.0),
+		forward:    forward,
+	}
+}
+
+// MakeAxonometric places an orthographic camera at distance from look_at,
+// rotated azimuth degrees about +Y (0 is the usual viewer side, -Z) and
+// raised elevation degrees above the XZ plane.
+func MakeAxonometric(look_at vectors.Vector, azimuth float64, elevation float64, distance float64, view_height float64, width int, height int) Orthographic {
+	az := azimuth * math.Pi / 180.0
+	el := elevation * math.Pi / 180.0
+	offset := &vectors.Vector{
+		X: math.Cos(el) * math.Sin(az),
+		Y: math.Sin(el),
+		Z: -math.Cos(el) * math.Cos(az),
+	}
+	look_from := look_at.Add(offset.MultiplyScalar(distance))
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+	if math.Abs(elevation) == 90.0 {
+		// Looking straight up or down, so +Y can't be up on screen
+		up = vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	}
+
+	return MakeOrthographic(*look_from, look_at, up, view_height, width, height)
+}
+
+func MakeIsometric(look_at vectors.Vector, distance float64, view_height float64, width int, height int) Orthographic {
+	return MakeAxonometric(look_at, 45.0, isometric_elevation, distance, view_height, width, height)
+}
+
+func MakeDimetric(look_at vectors.Vector, distance float64, view_height float64, width int, height int) Orthographic {
+	return MakeAxonometric(look_at, 45.0, dimetric_elevation, distance, view_height, width, height)
+}
+
+type Orthographic struct {
+	LookFrom   vectors.Vector // Centre of the view volume's near face, rays start on this plane
+	LookAt     vectors.Vector
+	Up         vectors.Vector
+	ViewHeight float64 // Height of the view volume in world units, the width follows from Width / Height
+	Width      int
+	Height     int
+
+	// Half extents of the near face, only computed once per camera
+	horizontal *vectors.Vector
+	vertical   *vectors.Vector
+	forward    *vectors.Vector
+}
+
+func (c Orthographic) Resolution() (int, int) {
+	return c.Width, c.Height
+}
+
+func (c Orthographic) Ray(x float64, y float64) rays.Ray {
+	sx, sy := screenCoords(x, y, c.Width, c.Height)
+	origin := c.LookFrom.Add(c.horizontal.MultiplyScalar(sx)).Add(c.vertical.MultiplyScalar(sy))
+	direction := *c.forward
+	return rays.MakeRay(origin, &direction)
+}
@@ -0,0 +1,77 @@
+package cameras
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestOrthographic_Ray(t *testing.T) {
+	// Looking down +Z with a 2 x 4 view volume
+	c := MakeOrthographic(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: -5.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		2.0,
+		200,
+		100,
+	)
+	type args struct {
+		x float64
+		y float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want rays.Ray
+	}{
+		{
+			name: "Centre of image",
+			args: args{x: 100.0, y: 50.0},
+			want: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+		},
+		{
+			name: "Top left corner",
+			args: args{x: 0.0, y: 0.0},
+			want: rays.MakeRay(&vectors.Vector{X: -2.0, Y: 1.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+		},
+		{
+			name: "Bottom right corner",
+			args: args{x: 200.0, y: 100.0},
+			want: rays.MakeRay(&vectors.Vector{X: 2.0, Y: -1.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Ray(tt.args.x, tt.args.y); !got.CloseTo(tt.want) {
+				t.Errorf("Orthographic.Ray() = %s, want %s", got.Print(), tt.want.Print())
+			}
+		})
+	}
+}
+
+func TestMakeIsometric(t *testing.T) {
+	c := MakeIsometric(vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, 10.0, 4.0, 100, 100)
+	// The view direction is down the (-1, -1, 1) diagonal, so all three
+	// axes are equally foreshortened.
+	k := 1.0 / math.Sqrt(3.0)
+	want := rays.MakeRay(&vectors.Vector{X: 10.0 * k, Y: 10.0 * k, Z: -10.0 * k}, &vectors.Vector{X: -k, Y: -k, Z: k})
+	if got := c.Ray(50.0, 50.0); !got.CloseTo(want) {
+		t.Errorf("Isometric Ray() = %s, want %s", got.Print(), want.Print())
+	}
+}
+
+func TestMakeDimetric(t *testing.T) {
+	c := MakeDimetric(vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, 10.0, 4.0, 100, 100)
+	right, up := *c.horizontal, *c.vertical
+	right.Normalise()
+	up.Normalise()
+	// Lines along X and Z go one up on screen for every two across
+	for _, step := range []vectors.Vector{{X: 1.0, Y: 0.0, Z: 0.0}, {X: 0.0, Y: 0.0, Z: 1.0}} {
+		if slope := math.Abs(step.Dot(&up) / step.Dot(&right)); math.Abs(slope-0.5) > 1e-6 {
+			t.Errorf("Dimetric screen slope of %v = %v, want 0.5", step, slope)
+		}
+	}
+}
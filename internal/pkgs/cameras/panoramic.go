@@ -0,0 +1,93 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Faces of a cube map in the usual order. Orientations follow the left
+// handed (Direct3D) convention, matching the scene's axes.
+var CubemapFaceNames = [6]string{"posx", "negx", "posy", "negy", "posz", "negz"}
+
+var cubemap_directions = [6]vectors.Vector{
+	{X: 1.0, Y: 0.0, Z: 0.0},
+	{X: -1.0, Y: 0.0, Z: 0.0},
+	{X: 0.0, Y: 1.0, Z: 0.0},
+	{X: 0.0, Y: -1.0, Z: 0.0},
+	{X: 0.0, Y: 0.0, Z: 1.0},
+	{X: 0.0, Y: 0.0, Z: -1.0},
+}
+
+var cubemap_ups = [6]vectors.Vector{
+	{X: 0.0, Y: 1.0, Z: 0.0},
+	{X: 0.0, Y: 1.0, Z: 0.0},
+	{X: 0.0, Y: 0.0, Z: -1.0},
+	{X: 0.0, Y: 0.0, Z: 1.0},
+	{X: 0.0, Y: 1.0, Z: 0.0},
+	{X: 0.0, Y: 1.0, Z: 0.0},
+}
+
+func MakeCubemap(position vectors.Vector, size int) [6]Perspective {
+	// Six square 90 degree cameras which together see in every direction.
+	var faces [6]Perspective
+	for i := range faces {
+		look_at := position.Add(&cubemap_directions[i])
+		faces[i] = MakePerspective(position, *look_at, cubemap_ups[i], 90.0, size, size)
+	}
+	return faces
+}
+
+func MakeEquirectangular(position vectors.Vector, look_at vectors.Vector, up vectors.Vector, width int) Equirectangular {
+	if width < 2 || width%2 != 0 {
+		log.Fatal("Equirectangular width must be a positive even number.")
+	}
+	right, true_up, forward := viewBasis(&position, &look_at, &up)
+
+	return Equirectangular{
+		Position: position,
+		LookAt:   look_at,
+		Up:       up,
+		Width:    width,
+		right:    right,
+		up:       true_up,
+		forward:  forward,
+	}
+}
+
+// Equirectangular sees the whole sphere around Position, with longitude
+// across the image and latitude down it. LookAt is in the centre of the
+// image and the height is always half the width.
+type Equirectangular struct {
+	Position vectors.Vector
+	LookAt   vectors.Vector
+	Up       vectors.Vector
+	Width    int
+
+	right   *vectors.Vector
+	up      *vectors.Vector
+	forward *vectors.Vector
+}
+
+func (c Equirectangular) Resolution() (int, int) {
+	return c.Width, c.Width / 2
+}
+
+func (c Equirectangular) Ray(x float64, y float64) rays.Ray {
+	_, height := c.Resolution()
+	longitude := (x/float64(c.Width) - 0.5) * 2.0 * math.Pi
+	latitude := (0.5 - y/float64(height)) * math.Pi
+	origin := c.Position
+	return rays.MakeRay(&origin, sphericalDirection(longitude, latitude, c.right, c.up, c.forward))
+}
+
+func sphericalDirection(longitude float64, latitude float64, right *vectors.Vector, up *vectors.Vector, forward *vectors.Vector) *vectors.Vector {
+	// Unit direction for angles in radians, measured from forward towards right and up.
+	return right.MultiplyScalar(math.Cos(latitude) * math.Sin(longitude)).Add(
+		up.MultiplyScalar(math.Sin(latitude)),
+	).Add(
+		forward.MultiplyScalar(math.Cos(latitude) * math.Cos(longitude)),
+	)
+}
@@ -0,0 +1,73 @@
+package cameras
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestEquirectangular_Ray(t *testing.T) {
+	c := MakeEquirectangular(
+		vectors.Vector{X: 1.0, Y: 2.0, Z: 3.0},
+		vectors.Vector{X: 1.0, Y: 2.0, Z: 4.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		200,
+	)
+	if width, height := c.Resolution(); width != 200 || height != 100 {
+		t.Errorf("Equirectangular.Resolution() = %d, %d, want 200, 100", width, height)
+	}
+	type args struct {
+		x float64
+		y float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want *vectors.Vector
+	}{
+		{name: "Centre looks forward", args: args{x: 100.0, y: 50.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}},
+		{name: "Three quarters across looks right", args: args{x: 150.0, y: 50.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+		{name: "Left edge looks backwards", args: args{x: 0.0, y: 50.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}},
+		{name: "Top edge looks up", args: args{x: 30.0, y: 0.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Bottom edge looks down", args: args{x: 170.0, y: 100.0}, want: &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Ray(tt.args.x, tt.args.y)
+			if !got.Direction.CloseTo(tt.want) || !got.Origin.CloseTo(&c.Position) {
+				t.Errorf("Equirectangular.Ray() = %s, want direction %v", got.Print(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeCubemap(t *testing.T) {
+	faces := MakeCubemap(vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, 64)
+	for i, face := range faces {
+		if got := face.Ray(32.0, 32.0); !got.Direction.CloseTo(&cubemap_directions[i]) {
+			t.Errorf("Cube map face %s looks along %v, want %v", CubemapFaceNames[i], got.Direction, cubemap_directions[i])
+		}
+	}
+	// Neighbouring faces share their edges, so the cube has no seams
+	seams := []struct {
+		name   string
+		face_a int
+		xa, ya float64
+		face_b int
+		xb, yb float64
+	}{
+		{name: "+Z right edge is +X left edge", face_a: 4, xa: 64.0, ya: 10.0, face_b: 0, xb: 0.0, yb: 10.0},
+		{name: "+X right edge is -Z left edge", face_a: 0, xa: 64.0, ya: 10.0, face_b: 5, xb: 0.0, yb: 10.0},
+		{name: "+Z top edge is +Y bottom edge", face_a: 4, xa: 20.0, ya: 0.0, face_b: 2, xb: 20.0, yb: 64.0},
+		{name: "+Z bottom edge is -Y top edge", face_a: 4, xa: 20.0, ya: 64.0, face_b: 3, xb: 20.0, yb: 0.0},
+	}
+	for _, tt := range seams {
+		t.Run(tt.name, func(t *testing.T) {
+			a := faces[tt.face_a].Ray(tt.xa, tt.ya)
+			b := faces[tt.face_b].Ray(tt.xb, tt.yb)
+			if !a.CloseTo(b) {
+				t.Errorf("Seam rays differ: %s and %s", a.Print(), b.Print())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakePerspective(look_from vectors.Vector, look_at vectors.Vector, up vectors.Vector, vfov float64, width int, height int) Perspective {
+	if width <= 0 || height <= 0 {
+		log.Fatal("Width and height must be positive.")
+	}
+	if vfov <= 0.0 || vfov >= 180.0 {
+		log.Fatal("Vertical field of view must be between 0 and 180 degrees.")
+	}
+	right, true_up, forward := viewBasis(&look_from, &look_at, &up)
+	half_height := math.Tan(vfov * math.Pi / 360.0)
+	half_width := half_height * float64(width) / float64(height)
+
+	return Perspective{
+		LookFrom:   look_from,
+		LookAt:     look_at,
+		Up:         up,
+		VFov:       vfov,
+		Width:      width,
+		Height:     height,
+		horizontal: right.MultiplyScalar(half_width),
+		vertical:   true_up.MultiplyScalar(half_height),
+		forward:    forward,
+	}
+}
+
+type Perspective struct {
+	LookFrom vectors.Vector
+	LookAt   vectors.Vector
+	Up       vectors.Vector
+	VFov     float64 // Vertical field of view in degrees, the horizontal one follows from Width / Height
+	Width    int
+	Height   int
+
+	// Half extents of the image plane at unit distance, only computed once per camera
+	horizontal *vectors.Vector
+	vertical   *vectors.Vector
+	forward    *vectors.Vector
+}
+
+func (c Perspective) Resolution() (int, int) {
+	return c.Width, c.Height
+}
+
+func (c Perspective) Ray(x float64, y float64) rays.Ray {
+	sx, sy := screenCoords(x, y, c.Width, c.Height)
+	direction := c.forward.Add(c.horizontal.MultiplyScalar(sx)).Add(c.vertical.MultiplyScalar(sy))
+	origin := c.LookFrom
+	return rays.MakeRay(&origin, direction)
+}
@@ -0,0 +1,95 @@
+package cameras
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestPerspective_Ray(t *testing.T) {
+	// A 90 degree camera at the origin looking down +Z, so the image plane
+	// at unit distance spans [-2, 2] x [-1, 1].
+	c := MakePerspective(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		90.0,
+		200,
+		100,
+	)
+	type args struct {
+		x float64
+		y float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want rays.Ray
+	}{
+		{
+			name: "Centre of image",
+			args: args{x: 100.0, y: 50.0},
+			want: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+		},
+		{
+			name: "Top left corner",
+			args: args{x: 0.0, y: 0.0},
+			want: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: -2.0, Y: 1.0, Z: 1.0}),
+		},
+		{
+			name: "Bottom right corner",
+			args: args{x: 200.0, y: 100.0},
+			want: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 2.0, Y: -1.0, Z: 1.0}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Ray(tt.args.x, tt.args.y); !got.CloseTo(tt.want) {
+				t.Errorf("Perspective.Ray() = %s, want %s", got.Print(), tt.want.Print())
+			}
+		})
+	}
+}
+
+func TestPerspective_LookAt(t *testing.T) {
+	// Looking down -X from the side, the centre ray must point at look_at.
+	c := MakePerspective(
+		vectors.Vector{X: 10.0, Y: 1.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		45.0,
+		10,
+		10,
+	)
+	want := rays.MakeRay(&vectors.Vector{X: 10.0, Y: 1.0, Z: 0.0}, &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0})
+	if got := c.Ray(5.0, 5.0); !got.CloseTo(want) {
+		t.Errorf("Perspective.Ray() = %s, want %s", got.Print(), want.Print())
+	}
+	// And +X on screen is to the camera's right, which is +Z here.
+	if got := c.Ray(10.0, 5.0); !(got.Direction.Z > 0.0) {
+		t.Errorf("Perspective.Ray() right edge = %s, want positive Z direction", got.Print())
+	}
+}
+
+func TestRayGenerator(t *testing.T) {
+	c := MakePerspective(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		60.0,
+		4,
+		3,
+	)
+	generate := RayGenerator(c)
+	if got := Samples(c); got != 1 {
+		t.Errorf("Samples() = %d, want 1", got)
+	}
+	// Pixels are sampled through their centres
+	if got, want := generate(0, 0, 0), c.Ray(0.5, 0.5); !got.CloseTo(want) {
+		t.Errorf("RayGenerator()(0, 0, 0) = %s, want %s", got.Print(), want.Print())
+	}
+	if got, want := generate(3, 2, 0), c.Ray(3.5, 2.5); !got.CloseTo(want) {
+		t.Errorf("RayGenerator()(3, 2, 0) = %s, want %s", got.Print(), want.Print())
+	}
+}
@@ -0,0 +1,128 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakeStereo(look_from vectors.Vector, look_at vectors.Vector, up vectors.Vector, vfov float64, width int, height int, interocular float64, convergence float64) (StereoEye, StereoEye) {
+	// Left and right eyes either side of look_from.
+	if interocular < 0.0 {
+		log.Fatal("Interocular distance must not be negative.")
+	}
+	if convergence < 0.0 {
+		log.Fatal("Convergence distance must not be negative.")
+	}
+	centre := MakePerspective(look_from, look_at, up, vfov, width, height)
+	right, _, _ := viewBasis(&look_from, &look_at, &up)
+	left_eye := StereoEye{Perspective: centre, Offset: -interocular / 2.0, Convergence: convergence, right: right}
+	right_eye := StereoEye{Perspective: centre, Offset: interocular / 2.0, Convergence: convergence, right: right}
+	return left_eye, right_eye
+}
+
+// StereoEye is one eye of a stereo pair, moved Offset to the right of the
+// centre camera. Rather than toeing in, the eyes keep the centre camera's
+// view direction and shift their image planes so both see the same thing at
+// Convergence, which keeps vertical parallax out of the pair.
+type StereoEye struct {
+	Perspective
+	Offset      float64 // Distance to the right of the centre camera, negative for the left eye
+	Convergence float64 // Distance of zero parallax along the view direction, 0 for parallel eyes
+
+	right *vectors.Vector
+}
+
+func (c StereoEye) Ray(x float64, y float64) rays.Ray {
+	centre := c.Perspective.Ray(x, y)
+	origin := c.LookFrom.Add(c.right.MultiplyScalar(c.Offset))
+	if c.Convergence == 0.0 {
+		return rays.MakeRay(origin, centre.Direction)
+	}
+	// Scale to unit length along forward, so this is on the plane of convergence
+	direction := centre.Direction.MultiplyScalar(c.Convergence / centre.Direction.Dot(c.forward))
+	target := c.LookFrom.Add(direction)
+	return rays.MakeRay(origin, target.Subtract(origin))
+}
+
+func MakeOmniStereo(position vectors.Vector, look_at vectors.Vector, up vectors.Vector, width int, interocular float64, convergence float64) (OmniStereoEye, OmniStereoEye) {
+	// Left and right eyes of an omni-directional stereo panorama.
+	if interocular < 0.0 {
+		log.Fatal("Interocular distance must not be negative.")
+	}
+	if convergence < 0.0 {
+		log.Fatal("Convergence distance must not be negative.")
+	}
+	centre := MakeEquirectangular(position, look_at, up, width)
+	left_eye := OmniStereoEye{Equirectangular: centre, Offset: -interocular / 2.0, Convergence: convergence}
+	right_eye := OmniStereoEye{Equirectangular: centre, Offset: interocular / 2.0, Convergence: convergence}
+	return left_eye, right_eye
+}
+
+// OmniStereoEye is an equirectangular panorama where, for every longitude,
+// the eye sits on a circle of radius |Offset| as if the viewer had turned
+// their head to look that way. Looking up or down the eyes come together,
+// as there is no one way for the head to be turned.
+type OmniStereoEye struct {
+	Equirectangular
+	Offset      float64 // Distance to the right of the centre of the head, negative for the left eye
+	Convergence float64 // Distance of zero parallax, 0 for parallel eyes
+}
+
+func (c OmniStereoEye) Ray(x float64, y float64) rays.Ray {
+	_, height := c.Resolution()
+	longitude := (x/float64(c.Width) - 0.5) * 2.0 * math.Pi
+	latitude := (0.5 - y/float64(height)) * math.Pi
+	direction := sphericalDirection(longitude, latitude, c.right, c.up, c.forward)
+
+	// The head's right hand side when facing this longitude, fading out at the poles
+	head_right := c.right.MultiplyScalar(math.Cos(longitude)).Add(c.forward.MultiplyScalar(-math.Sin(longitude)))
+	origin := c.Position.Add(head_right.MultiplyScalar(c.Offset * math.Cos(latitude)))
+	if c.Convergence == 0.0 {
+		return rays.MakeRay(origin, direction)
+	}
+	target := c.Position.Add(direction.MultiplyScalar(c.Convergence))
+	return rays.MakeRay(origin, target.Subtract(origin))
+}
+
+// SideBySide puts the images from two cameras of the same resolution next
+// to each other, Left on the left.
+type SideBySide struct {
+	Left  Camera
+	Right Camera
+}
+
+func (c SideBySide) Resolution() (int, int) {
+	width, height := c.Left.Resolution()
+	return 2 * width, height
+}
+
+func (c SideBySide) Ray(x float64, y float64) rays.Ray {
+	width, _ := c.Left.Resolution()
+	if x < float64(width) {
+		return c.Left.Ray(x, y)
+	}
+	return c.Right.Ray(x-float64(width), y)
+}
+
+// TopBottom stacks the images from two cameras of the same resolution, Top
+// above Bottom. VR players expect the left eye on top.
+type TopBottom struct {
+	Top    Camera
+	Bottom Camera
+}
+
+func (c TopBottom) Resolution() (int, int) {
+	width, height := c.Top.Resolution()
+	return width, 2 * height
+}
+
+func (c TopBottom) Ray(x float64, y float64) rays.Ray {
+	_, height := c.Top.Resolution()
+	if y < float64(height) {
+		return c.Top.Ray(x, y)
+	}
+	return c.Bottom.Ray(x, y-float64(height))
+}
@@ -0,0 +1,126 @@
+package cameras
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestMakeStereo(t *testing.T) {
+	look_from := vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}
+	look_at := vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+
+	left, right := MakeStereo(look_from, look_at, up, 60.0, 80, 60, 0.064, 2.0)
+	for _, xy := range [][2]float64{{40.0, 30.0}, {3.5, 7.5}, {79.5, 59.5}} {
+		l := left.Ray(xy[0], xy[1])
+		r := right.Ray(xy[0], xy[1])
+		if !l.Origin.CloseTo(&vectors.Vector{X: -0.032, Y: 0.0, Z: 0.0}) || !r.Origin.CloseTo(&vectors.Vector{X: 0.032, Y: 0.0, Z: 0.0}) {
+			t.Errorf("Eye origins = %v and %v, want 64mm apart along X", l.Origin, r.Origin)
+		}
+		// Zero parallax on the plane of convergence
+		l_hit := l.Origin.Add(l.Direction.MultiplyScalar(2.0 / l.Direction.Z))
+		r_hit := r.Origin.Add(r.Direction.MultiplyScalar(2.0 / r.Direction.Z))
+		if !l_hit.CloseTo(r_hit) {
+			t.Errorf("Pixel %v: eyes meet the convergence plane at %v and %v", xy, l_hit, r_hit)
+		}
+		// and no vertical parallax anywhere
+		if !utils.Close_enough(l.Direction.Y/l.Direction.Z, r.Direction.Y/r.Direction.Z) {
+			t.Errorf("Pixel %v: vertical parallax between %s and %s", xy, l.Print(), r.Print())
+		}
+	}
+
+	parallel_left, parallel_right := MakeStereo(look_from, look_at, up, 60.0, 80, 60, 0.064, 0.0)
+	if l, r := parallel_left.Ray(10.0, 10.0), parallel_right.Ray(10.0, 10.0); !l.Direction.CloseTo(r.Direction) {
+		t.Errorf("Parallel eyes have directions %v and %v", l.Direction, r.Direction)
+	}
+}
+
+func TestMakeOmniStereo(t *testing.T) {
+	left, right := MakeOmniStereo(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		200,
+		0.064,
+		0.0,
+	)
+	tests := []struct {
+		name       string
+		x          float64
+		y          float64
+		want_left  *vectors.Vector
+		want_right *vectors.Vector
+	}{
+		{
+			name:       "Looking forward",
+			x:          100.0,
+			y:          50.0,
+			want_left:  &vectors.Vector{X: -0.032, Y: 0.0, Z: 0.0},
+			want_right: &vectors.Vector{X: 0.032, Y: 0.0, Z: 0.0},
+		},
+		{
+			name:       "Looking right",
+			x:          150.0,
+			y:          50.0,
+			want_left:  &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.032},
+			want_right: &vectors.Vector{X: 0.0, Y: 0.0, Z: -0.032},
+		},
+		{
+			name:       "Looking straight up",
+			x:          20.0,
+			y:          0.0,
+			want_left:  &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+			want_right: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := left.Ray(tt.x, tt.y); !got.Origin.CloseTo(tt.want_left) {
+				t.Errorf("Left eye origin = %v, want %v", got.Origin, tt.want_left)
+			}
+			if got := right.Ray(tt.x, tt.y); !got.Origin.CloseTo(tt.want_right) {
+				t.Errorf("Right eye origin = %v, want %v", got.Origin, tt.want_right)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	left, right := MakeStereo(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		60.0, 40, 30, 0.064, 2.0,
+	)
+	c := SideBySide{Left: left, Right: right}
+	if width, height := c.Resolution(); width != 80 || height != 30 {
+		t.Errorf("SideBySide.Resolution() = %d, %d, want 80, 30", width, height)
+	}
+	if got, want := c.Ray(10.5, 5.5), left.Ray(10.5, 5.5); !got.CloseTo(want) {
+		t.Errorf("SideBySide.Ray() left half = %s, want %s", got.Print(), want.Print())
+	}
+	if got, want := c.Ray(50.5, 5.5), right.Ray(10.5, 5.5); !got.CloseTo(want) {
+		t.Errorf("SideBySide.Ray() right half = %s, want %s", got.Print(), want.Print())
+	}
+}
+
+func TestTopBottom(t *testing.T) {
+	left, right := MakeOmniStereo(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		40, 0.064, 0.0,
+	)
+	c := TopBottom{Top: left, Bottom: right}
+	if width, height := c.Resolution(); width != 40 || height != 40 {
+		t.Errorf("TopBottom.Resolution() = %d, %d, want 40, 40", width, height)
+	}
+	if got, want := c.Ray(10.5, 5.5), left.Ray(10.5, 5.5); !got.CloseTo(want) {
+		t.Errorf("TopBottom.Ray() top half = %s, want %s", got.Print(), want.Print())
+	}
+	if got, want := c.Ray(10.5, 25.5), right.Ray(10.5, 5.5); !got.CloseTo(want) {
+		t.Errorf("TopBottom.Ray() bottom half = %s, want %s", got.Print(), want.Print())
+	}
+}
@@ -0,0 +1,121 @@
+package cameras
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakeThinLens(
+	look_from vectors.Vector,
+	look_at vectors.Vector,
+	up vectors.Vector,
+	vfov float64,
+	width int,
+	height int,
+	aperture float64,
+	focus_distance float64,
+	blades int,
+	samples int,
+) ThinLens {
+	if aperture < 0.0 {
+		log.Fatal("Aperture radius must not be negative.")
+	}
+	if focus_distance <= 0.0 {
+		log.Fatal("Focus distance must be positive.")
+	}
+	if blades != 0 && blades < 3 {
+		log.Fatal("A polygonal aperture needs at least 3 blades, use 0 for a circle.")
+	}
+	if samples < 1 {
+		log.Fatal("At least one lens sample per pixel is needed.")
+	}
+	right, true_up, _ := viewBasis(&look_from, &look_at, &up)
+
+	return ThinLens{
+		Perspective:   MakePerspective(look_from, look_at, up, vfov, width, height),
+		Aperture:      aperture,
+		FocusDistance: focus_distance,
+		Blades:        blades,
+		NumSamples:    samples,
+		right:         right,
+		up:            true_up,
+	}
+}
+
+// ThinLens is a perspective camera whose rays start anywhere on a lens of
+// radius Aperture rather than at a single point, so only the plane
+// FocusDistance in front of the camera is in perfect focus.
+type ThinLens struct {
+	Perspective
+	Aperture      float64 // Lens radius in world units, 0 is a pinhole
+	FocusDistance float64 // Distance along the view direction to the plane of focus
+	Blades        int     // Number of aperture blades, 0 for a circular aperture
+	NumSamples    int     // Lens samples per pixel
+
+	right *vectors.Vector
+	up    *vectors.Vector
+}
+
+func (c ThinLens) Samples() int {
+	return c.NumSamples
+}
+
+func (c ThinLens) SampleRay(x float64, y float64, sample int) rays.Ray {
+	sx, sy := screenCoords(x, y, c.Width, c.Height)
+	pinhole_direction := c.forward.Add(c.horizontal.MultiplyScalar(sx)).Add(c.vertical.MultiplyScalar(sy))
+	// The unnormalised direction has unit length along forward, so this is
+	// on the plane of focus.
+	focus_point := c.LookFrom.Add(pinhole_direction.MultiplyScalar(c.FocusDistance))
+
+	u, v := hammersley(sample, c.NumSamples)
+	// Rotate the sample pattern per pixel, otherwise every pixel uses the
+	// same lens points and out of focus areas show the pattern.
+	u, v = scramble(u, v, x, y)
+	lx, ly := c.lensPoint(u, v)
+	origin := c.LookFrom.Add(c.right.MultiplyScalar(lx * c.Aperture)).Add(c.up.MultiplyScalar(ly * c.Aperture))
+
+	return rays.MakeRay(origin, focus_point.Subtract(origin))
+}
+
+func (c ThinLens) lensPoint(u float64, v float64) (float64, float64) {
+	// Maps a point in the unit square onto the unit aperture, uniformly by area.
+	if c.Blades == 0 {
+		r := math.Sqrt(u)
+		theta := 2.0 * math.Pi * v
+		return r * math.Cos(theta), r * math.Sin(theta)
+	}
+	// Pick one of the triangles fanning out from the centre of the polygon,
+	// then a point within it.
+	n := float64(c.Blades)
+	blade := math.Floor(u * n)
+	u = u*n - blade
+	theta0 := 2.0 * math.Pi * blade / n
+	theta1 := 2.0 * math.Pi * (blade + 1.0) / n
+	r := math.Sqrt(u)
+	return r * ((1.0-v)*math.Cos(theta0) + v*math.Cos(theta1)), r * ((1.0-v)*math.Sin(theta0) + v*math.Sin(theta1))
+}
+
+func hammersley(i int, n int) (float64, float64) {
+	// Point i of an n point Hammersley set, spread evenly over the unit square.
+	var inverse float64
+	f := 0.5
+	for j := i; j > 0; j >>= 1 {
+		if j&1 == 1 {
+			inverse += f
+		}
+		f *= 0.5
+	}
+	return (float64(i) + 0.5) / float64(n), inverse
+}
+
+func scramble(u float64, v float64, x float64, y float64) (float64, float64) {
+	// Cranley-Patterson rotation by a cheap hash of the pixel coordinates.
+	du := math.Abs(math.Sin(x*12.9898+y*78.233)) * 43758.5453
+	dv := math.Abs(math.Sin(x*39.3467+y*11.135)) * 24634.6345
+	u += du - math.Floor(du)
+	v += dv - math.Floor(dv)
+	return u - math.Floor(u), v - math.Floor(v)
+}
@@ -0,0 +1,78 @@
+package cameras
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func makeTestThinLens(aperture float64, blades int) ThinLens {
+	return MakeThinLens(
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+		vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		60.0,
+		100,
+		50,
+		aperture,
+		10.0,
+		blades,
+		16,
+	)
+}
+
+func TestThinLens_SampleRay(t *testing.T) {
+	tests := []struct {
+		name     string
+		aperture float64
+		blades   int
+	}{
+		{name: "Pinhole", aperture: 0.0, blades: 0},
+		{name: "Circular aperture", aperture: 0.5, blades: 0},
+		{name: "Hexagonal aperture", aperture: 0.5, blades: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := makeTestThinLens(tt.aperture, tt.blades)
+			// Every sample for a pixel passes through the same point on the plane of focus
+			pinhole := c.Ray(30.5, 20.5)
+			focus_point := pinhole.Origin.Add(pinhole.Direction.MultiplyScalar(c.FocusDistance / pinhole.Direction.Z))
+			for k := 0; k < c.Samples(); k++ {
+				got := c.SampleRay(30.5, 20.5, k)
+				if lens_radius := got.Origin.Magnitude(); lens_radius > tt.aperture+1e-9 {
+					t.Errorf("ThinLens.SampleRay(%d) starts %v from the lens centre, want at most %v", k, lens_radius, tt.aperture)
+				}
+				hit := got.Origin.Add(got.Direction.MultiplyScalar((c.FocusDistance - got.Origin.Z) / got.Direction.Z))
+				if !hit.CloseTo(focus_point) {
+					t.Errorf("ThinLens.SampleRay(%d) meets the plane of focus at %v, want %v", k, hit, focus_point)
+				}
+			}
+		})
+	}
+}
+
+func TestThinLens_lensPoint(t *testing.T) {
+	// All points of a square aperture land inside the square with corners on the unit circle.
+	c := makeTestThinLens(1.0, 4)
+	for i := 0; i < 64; i++ {
+		u, v := hammersley(i, 64)
+		x, y := c.lensPoint(u, v)
+		if math.Abs(x)+math.Abs(y) > 1.0+1e-9 {
+			t.Errorf("lensPoint(%v, %v) = (%v, %v), outside the aperture", u, v, x, y)
+		}
+	}
+}
+
+func TestThinLens_RayGenerator(t *testing.T) {
+	c := makeTestThinLens(0.5, 0)
+	if got := Samples(c); got != 16 {
+		t.Errorf("Samples() = %d, want 16", got)
+	}
+	generate := RayGenerator(c)
+	for k := 0; k < c.Samples(); k++ {
+		if got, want := generate(7, 3, k), c.SampleRay(7.5, 3.5, k); !got.CloseTo(want) {
+			t.Errorf("RayGenerator()(7, 3, %d) = %s, want %s", k, got.Print(), want.Print())
+		}
+	}
+}
@@ -18,6 +18,8 @@ func MakeMaterial(colour color.RGBA, diffuse float64, specular float64, ambient
 		Specular_consts: Specular_consts,
 		Ambient_consts:  Ambient_consts,
 		Matte:           matte,
+
+		Refractive_index: 1.0,
 	}
 }
 
@@ -32,7 +34,28 @@ type Material struct {
 	Specular_consts [3]float64
 	Ambient_consts  [3]float64
 
-	Matte float64 // \in [0, 1], higher values = less reflection / refraction
+	Matte float64 // \in [0, 1], higher values = less reflection. Not used when transparent
+
+	Transparency     float64 // \in [0, 1], 0 = opaque. Reflects by Fresnel rather than Matte
+	Refractive_index float64 // 1.0 for air
 
 	Ambient_color color.RGBA // Only needs to be computed once per scene
 }
+
+func (m Material) WithColor(colour color.RGBA) Material {
+	// The same material in another colour, e.g. from a mesh's vertex colours.
+	// Specular highlights keep their colour.
+	m.Color = colour
+	m.Diffuse_consts = [3]float64{float64(colour.R) * m.Diffuse_const, float64(colour.G) * m.Diffuse_const, float64(colour.B) * m.Diffuse_const}
+	m.Ambient_consts = [3]float64{float64(colour.R) * m.Ambient_const, float64(colour.G) * m.Ambient_const, float64(colour.B) * m.Ambient_const}
+	m.Ambient_color = color.RGBA{}
+	return m
+}
+
+func (m Material) WithTransparency(transparency float64, refractive_index float64) Material {
+	// The same material letting light through, e.g. glass at 1.5 or water at
+	// 1.33. Some is still reflected, more at glancing angles.
+	m.Transparency = transparency
+	m.Refractive_index = refractive_index
+	return m
+}
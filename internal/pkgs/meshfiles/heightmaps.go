@@ -0,0 +1,40 @@
+package meshfiles
+
+import (
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+)
+
+func LoadHeightmap(path string) ([][]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadHeightmap(f)
+}
+
+// ReadHeightmap reads a grayscale PNG as heights from 0 for black to 1 for
+// white, ready for objects.MakeHeightfield. 16-bit images keep their full
+// precision; colour images are converted to gray. The bottom row of the
+// image comes first, so seen from above with z up the page the terrain
+// looks like the image.
+func ReadHeightmap(r io.Reader) ([][]float64, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	heights := make([][]float64, b.Dy())
+	for j := range heights {
+		heights[j] = make([]float64, b.Dx())
+		y := b.Max.Y - 1 - j
+		for i := range heights[j] {
+			gray := color.Gray16Model.Convert(img.At(b.Min.X+i, y)).(color.Gray16)
+			heights[j][i] = float64(gray.Y) / 0xffff
+		}
+	}
+	return heights, nil
+}
@@ -0,0 +1,50 @@
+package meshfiles
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+)
+
+func TestReadHeightmap(t *testing.T) {
+	img := image.NewGray16(image.Rect(0, 0, 2, 2))
+	img.SetGray16(0, 0, color.Gray16{Y: 0})
+	img.SetGray16(1, 0, color.Gray16{Y: 0xffff})
+	img.SetGray16(0, 1, color.Gray16{Y: 0x8000})
+	img.SetGray16(1, 1, color.Gray16{Y: 0x0001})
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+
+	heights, err := ReadHeightmap(&b)
+	if err != nil {
+		t.Fatalf("ReadHeightmap() error = %v", err)
+	}
+	// The bottom row first, keeping all 16 bits
+	want := [][]float64{
+		{float64(0x8000) / 0xffff, 1.0 / 0xffff},
+		{0.0, 1.0},
+	}
+	if len(heights) != len(want) {
+		t.Fatalf("ReadHeightmap() has %d rows, want %d", len(heights), len(want))
+	}
+	for j := range want {
+		if !utils.Slice_close_enough(heights[j], want[j]) {
+			t.Errorf("ReadHeightmap() row %d = %v, want %v", j, heights[j], want[j])
+		}
+	}
+	if heights[0][1] == 0.0 {
+		t.Errorf("ReadHeightmap() lost the low bits of a 16-bit image")
+	}
+}
+
+func TestReadHeightmap_notPNG(t *testing.T) {
+	if _, err := ReadHeightmap(bytes.NewReader([]byte("solid not a png"))); err == nil {
+		t.Errorf("ReadHeightmap() error = nil, want an error")
+	}
+}
@@ -0,0 +1,62 @@
+package meshfiles
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A Model is everything loaded from one file, split up by group and
+// material, in the order they first appear.
+type Model struct {
+	Groups []Group
+}
+
+type Group struct {
+	Name     string
+	Material string
+	Mesh     *objects.Mesh
+}
+
+func (m Model) Objects() []objects.Object {
+	// Ready to go in a scene's Objects.
+	objs := make([]objects.Object, len(m.Groups))
+	for i, group := range m.Groups {
+		objs[i] = group.Mesh
+	}
+	return objs
+}
+
+// Loader loads models the same way as the package's Load and Read
+// functions, except that with Cache set, each mesh's BVH comes from the
+// cache when it's already been built, and is saved there otherwise. Not
+// being able to save one is an error, like not being able to read the file.
+type Loader struct {
+	Cache *objects.AcceleratorCache
+}
+
+func (l Loader) makeMesh(positions []vectors.Vector, normals []vectors.Vector, uvs []objects.UV, colors []color.RGBA, faces [][3]int, m materials.Material) (*objects.Mesh, error) {
+	if l.Cache == nil {
+		return objects.MakeMesh(positions, normals, uvs, colors, faces, m), nil
+	}
+	mesh, err := objects.MakeMeshCached(*l.Cache, positions, normals, uvs, colors, faces, m)
+	if err != nil {
+		return nil, fmt.Errorf("caching a mesh's BVH: %v", err)
+	}
+	return mesh, nil
+}
+
+func DefaultMaterial() materials.Material {
+	// Used for faces without a material of their own, a plain light grey.
+	return materials.MakeMaterial(
+		color.RGBA{200, 200, 200, 0xff},
+		1.0/255.0,
+		0.001,
+		0.002,
+		50,
+		1.0,
+	)
+}
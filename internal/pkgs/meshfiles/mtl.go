@@ -0,0 +1,159 @@
+package meshfiles
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+)
+
+// ReadMTL reads a Wavefront material library, keyed by material name. Kd
+// becomes the material colour, Ks the specular colour, Ns the shininess,
+// d the opacity (Tr, its inverse, is also understood) and Ni the
+// refractive index. Anything else is ignored.
+func ReadMTL(r io.Reader) (map[string]materials.Material, error) {
+	library := make(map[string]materials.Material)
+	var name string
+	var entry mtlEntry
+
+	scanner := bufio.NewScanner(r)
+	line_number := 0
+	for scanner.Scan() {
+		line_number++
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] != "newmtl" && name == "" {
+			return nil, fmt.Errorf("mtl line %d: %q before any newmtl", line_number, fields[0])
+		}
+		var err error
+		switch fields[0] {
+		case "newmtl":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("mtl line %d: newmtl without a name", line_number)
+			}
+			if name != "" {
+				library[name] = entry.material()
+			}
+			name = strings.Join(fields[1:], " ")
+			entry = makeMtlEntry()
+		case "Kd":
+			entry.kd, err = parseFloats3(fields[1:])
+		case "Ks":
+			entry.ks, err = parseFloats3(fields[1:])
+		case "Ns":
+			entry.ns, err = parseFloat1(fields[1:])
+		case "d":
+			entry.d, err = parseFloat1(fields[1:])
+		case "Tr":
+			var tr float64
+			tr, err = parseFloat1(fields[1:])
+			entry.d = 1.0 - tr
+		case "Ni":
+			entry.ni, err = parseFloat1(fields[1:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mtl line %d: %v", line_number, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if name != "" {
+		library[name] = entry.material()
+	}
+
+	return library, nil
+}
+
+// The values read for one newmtl, before they're turned into a Material
+type mtlEntry struct {
+	kd [3]float64
+	ks [3]float64
+	ns float64
+	d  float64
+	ni float64
+}
+
+func makeMtlEntry() mtlEntry {
+	// The defaults from the MTL spec
+	return mtlEntry{
+		kd: [3]float64{0.8, 0.8, 0.8},
+		ns: 0.0,
+		d:  1.0,
+		ni: 1.0,
+	}
+}
+
+func (e mtlEntry) material() materials.Material {
+	default_material := DefaultMaterial()
+	// A diffuse const of 1/255 makes Diffuse_consts exactly Kd, which gives
+	// the Kd colour under a white light
+	m := materials.MakeMaterial(
+		colourFromFloats(e.kd),
+		1.0/255.0,
+		0.0,
+		default_material.Ambient_const,
+		e.ns,
+		default_material.Matte,
+	)
+	// MTL specular colours are absolute rather than relative to Kd
+	m.Specular_consts = e.ks
+	m.Transparency = 1.0 - clamp01(e.d)
+	m.Refractive_index = e.ni
+	return m
+}
+
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+func parseFloat1(fields []string) (float64, error) {
+	if len(fields) < 1 {
+		return 0.0, fmt.Errorf("missing value")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+func parseFloats3(fields []string) ([3]float64, error) {
+	var values [3]float64
+	if len(fields) == 1 {
+		// A single value is used for all three
+		v, err := strconv.ParseFloat(fields[0], 64)
+		return [3]float64{v, v, v}, err
+	}
+	if len(fields) < 3 {
+		return values, fmt.Errorf("need 3 values, got %d", len(fields))
+	}
+	for i := range values {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return values, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0.0, math.Min(1.0, x))
+}
+
+func colourFromFloats(rgb [3]float64) color.RGBA {
+	// [0, 1] floats to a colour
+	return color.RGBA{
+		uint8(math.Round(clamp01(rgb[0]) * 255.0)),
+		uint8(math.Round(clamp01(rgb[1]) * 255.0)),
+		uint8(math.Round(clamp01(rgb[2]) * 255.0)),
+		0xff,
+	}
+}
@@ -0,0 +1,100 @@
+package meshfiles
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+)
+
+const test_mtl = `# Two materials
+newmtl red_plastic
+Kd 1.0 0.0 0.0
+Ks 0.5 0.5 0.5
+Ns 96.0
+
+newmtl glass
+Kd 0.9
+d 0.25
+Ni 1.5
+illum 4
+`
+
+func TestReadMTL(t *testing.T) {
+	library, err := ReadMTL(strings.NewReader(test_mtl))
+	if err != nil {
+		t.Fatalf("ReadMTL() error = %v", err)
+	}
+	if len(library) != 2 {
+		t.Fatalf("ReadMTL() read %d materials, want 2", len(library))
+	}
+
+	red := library["red_plastic"]
+	if red.Color != (color.RGBA{0xff, 0, 0, 0xff}) {
+		t.Errorf("red_plastic colour = %v, want pure red", red.Color)
+	}
+	if !utils.Slice_close_enough(red.Diffuse_consts[:], []float64{1.0, 0.0, 0.0}) {
+		t.Errorf("red_plastic diffuse = %v, want Kd", red.Diffuse_consts)
+	}
+	if !utils.Slice_close_enough(red.Specular_consts[:], []float64{0.5, 0.5, 0.5}) {
+		t.Errorf("red_plastic specular = %v, want Ks", red.Specular_consts)
+	}
+	if red.Shininess_const != 96.0 || red.Transparency != 0.0 || red.Refractive_index != 1.0 {
+		t.Errorf("red_plastic shininess, transparency, IOR = %v, %v, %v", red.Shininess_const, red.Transparency, red.Refractive_index)
+	}
+
+	glass := library["glass"]
+	if glass.Color != (color.RGBA{230, 230, 230, 0xff}) {
+		t.Errorf("glass colour = %v, want a single Kd value used for all channels", glass.Color)
+	}
+	if !utils.Close_enough(glass.Transparency, 0.75) || glass.Refractive_index != 1.5 {
+		t.Errorf("glass transparency, IOR = %v, %v, want 0.75, 1.5", glass.Transparency, glass.Refractive_index)
+	}
+}
+
+func TestReadMTL_opacity(t *testing.T) {
+	// d is how opaque a material is, and Tr how transparent
+	tests := []struct {
+		name              string
+		mtl               string
+		want_transparency float64
+		want_ior          float64
+	}{
+		{name: "Opaque by default", mtl: "newmtl a\nKd 1 1 1\n", want_transparency: 0.0, want_ior: 1.0},
+		{name: "Dissolve", mtl: "newmtl a\nd 0.25\n", want_transparency: 0.75, want_ior: 1.0},
+		{name: "Transparency", mtl: "newmtl a\nTr 0.75\n", want_transparency: 0.75, want_ior: 1.0},
+		{name: "More than opaque", mtl: "newmtl a\nd 1.5\n", want_transparency: 0.0, want_ior: 1.0},
+		{name: "Water", mtl: "newmtl a\nd 0\nNi 1.33\n", want_transparency: 1.0, want_ior: 1.33},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			library, err := ReadMTL(strings.NewReader(tt.mtl))
+			if err != nil {
+				t.Fatalf("ReadMTL() error = %v", err)
+			}
+			got := library["a"]
+			if !utils.Close_enough(got.Transparency, tt.want_transparency) || !utils.Close_enough(got.Refractive_index, tt.want_ior) {
+				t.Errorf("ReadMTL() transparency, IOR = %v, %v, want %v, %v", got.Transparency, got.Refractive_index, tt.want_transparency, tt.want_ior)
+			}
+		})
+	}
+}
+
+func TestReadMTL_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mtl  string
+	}{
+		{name: "Property before newmtl", mtl: "Kd 1 1 1\n"},
+		{name: "Bad number", mtl: "newmtl a\nNs shiny\n"},
+		{name: "Short colour", mtl: "newmtl a\nKd 1 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadMTL(strings.NewReader(tt.mtl)); err == nil {
+				t.Errorf("ReadMTL() error = nil, want an error")
+			}
+		})
+	}
+}
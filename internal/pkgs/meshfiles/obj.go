@@ -0,0 +1,252 @@
+package meshfiles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func LoadOBJ(path string) (Model, error) {
+	return Loader{}.LoadOBJ(path)
+}
+
+func (l Loader) LoadOBJ(path string) (Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Model{}, err
+	}
+	defer f.Close()
+	return l.ReadOBJ(f, filepath.Dir(path))
+}
+
+// ReadOBJ reads a Wavefront OBJ file, looking for any material libraries it
+// names in dir. Faces with more than three vertices are split into a fan of
+// triangles. Each group (g or o) and material (usemtl) combination becomes
+// its own mesh, and a mesh only gets normals or UVs if all of its faces
+// have them. Coordinates are used as they are.
+func ReadOBJ(r io.Reader, dir string) (Model, error) {
+	return Loader{}.ReadOBJ(r, dir)
+}
+
+func (l Loader) ReadOBJ(r io.Reader, dir string) (Model, error) {
+	var positions []vectors.Vector
+	var normals []vectors.Vector
+	var uvs []objects.UV
+	var libraries []string
+
+	var parts []*objPart
+	part_indices := make(map[[2]string]int)
+	group_name := "default"
+	material_name := ""
+	current := func() *objPart {
+		key := [2]string{group_name, material_name}
+		i, ok := part_indices[key]
+		if !ok {
+			i = len(parts)
+			part_indices[key] = i
+			parts = append(parts, &objPart{group: group_name, material: material_name})
+		}
+		return parts[i]
+	}
+
+	scanner := bufio.NewScanner(r)
+	line_number := 0
+	for scanner.Scan() {
+		line_number++
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "v":
+			var xyz [3]float64
+			xyz, err = parseFloats3(fields[1:])
+			positions = append(positions, vectors.Vector{X: xyz[0], Y: xyz[1], Z: xyz[2]})
+		case "vn":
+			var xyz [3]float64
+			xyz, err = parseFloats3(fields[1:])
+			normals = append(normals, vectors.Vector{X: xyz[0], Y: xyz[1], Z: xyz[2]})
+		case "vt":
+			var uv objects.UV
+			uv.U, err = parseFloat1(fields[1:])
+			if err == nil && len(fields) > 2 {
+				uv.V, err = strconv.ParseFloat(fields[2], 64)
+			}
+			uvs = append(uvs, uv)
+		case "f":
+			if len(fields) < 4 {
+				err = fmt.Errorf("a face needs at least 3 vertices")
+				break
+			}
+			face := make([]objCorner, len(fields)-1)
+			for i, field := range fields[1:] {
+				face[i], err = parseCorner(field, len(positions), len(uvs), len(normals))
+				if err != nil {
+					break
+				}
+			}
+			if err == nil {
+				current().addFace(face)
+			}
+		case "g", "o":
+			group_name = "default"
+			if len(fields) > 1 {
+				group_name = strings.Join(fields[1:], " ")
+			}
+		case "usemtl":
+			material_name = ""
+			if len(fields) > 1 {
+				material_name = strings.Join(fields[1:], " ")
+			}
+		case "mtllib":
+			libraries = append(libraries, fields[1:]...)
+		}
+		if err != nil {
+			return Model{}, fmt.Errorf("obj line %d: %v", line_number, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Model{}, err
+	}
+
+	library := make(map[string]materials.Material)
+	for _, name := range libraries {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return Model{}, err
+		}
+		entries, err := ReadMTL(f)
+		f.Close()
+		if err != nil {
+			return Model{}, fmt.Errorf("%s: %v", name, err)
+		}
+		for material_name, m := range entries {
+			library[material_name] = m
+		}
+	}
+
+	var model Model
+	for _, part := range parts {
+		m, ok := library[part.material]
+		if !ok {
+			m = DefaultMaterial()
+		}
+		mesh, err := part.mesh(l, positions, uvs, normals, m)
+		if err != nil {
+			return Model{}, err
+		}
+		model.Groups = append(model.Groups, Group{
+			Name:     part.group,
+			Material: part.material,
+			Mesh:     mesh,
+		})
+	}
+	return model, nil
+}
+
+// One face corner's zero based indices, -1 where missing
+type objCorner struct {
+	position int
+	uv       int
+	normal   int
+}
+
+func parseCorner(field string, num_positions int, num_uvs int, num_normals int) (objCorner, error) {
+	// Parses v, v/vt, v//vn or v/vt/vn, where negative indices count back from the latest.
+	corner := objCorner{position: -1, uv: -1, normal: -1}
+	indices := strings.Split(field, "/")
+	if len(indices) > 3 {
+		return corner, fmt.Errorf("bad face vertex %q", field)
+	}
+	counts := [3]int{num_positions, num_uvs, num_normals}
+	targets := [3]*int{&corner.position, &corner.uv, &corner.normal}
+	for i, index := range indices {
+		if index == "" {
+			if i == 0 {
+				return corner, fmt.Errorf("face vertex %q has no position", field)
+			}
+			continue
+		}
+		n, err := strconv.Atoi(index)
+		if err != nil {
+			return corner, err
+		}
+		if n < 0 {
+			n = counts[i] + n
+		} else {
+			n--
+		}
+		if n < 0 || n >= counts[i] {
+			return corner, fmt.Errorf("face vertex %q refers to something not yet defined", field)
+		}
+		*targets[i] = n
+	}
+	return corner, nil
+}
+
+// The faces of one group and material, as triangles
+type objPart struct {
+	group     string
+	material  string
+	triangles [][3]objCorner
+}
+
+func (p *objPart) addFace(face []objCorner) {
+	// A fan around the first corner, which is fine for the convex faces modelling tools write.
+	for i := 1; i+1 < len(face); i++ {
+		p.triangles = append(p.triangles, [3]objCorner{face[0], face[i], face[i+1]})
+	}
+}
+
+func (p *objPart) mesh(l Loader, all_positions []vectors.Vector, all_uvs []objects.UV, all_normals []vectors.Vector, m materials.Material) (*objects.Mesh, error) {
+	// OBJ indexes positions, UVs and normals separately, meshes share one
+	// index, so each distinct combination becomes a vertex.
+	has_uvs, has_normals := true, true
+	for _, triangle := range p.triangles {
+		for _, corner := range triangle {
+			has_uvs = has_uvs && corner.uv >= 0
+			has_normals = has_normals && corner.normal >= 0
+		}
+	}
+
+	var positions []vectors.Vector
+	var uvs []objects.UV
+	var normals []vectors.Vector
+	vertex_indices := make(map[objCorner]int)
+	faces := make([][3]int, len(p.triangles))
+	for i, triangle := range p.triangles {
+		for j, corner := range triangle {
+			if !has_uvs {
+				corner.uv = -1
+			}
+			if !has_normals {
+				corner.normal = -1
+			}
+			index, ok := vertex_indices[corner]
+			if !ok {
+				index = len(positions)
+				vertex_indices[corner] = index
+				positions = append(positions, all_positions[corner.position])
+				if has_uvs {
+					uvs = append(uvs, all_uvs[corner.uv])
+				}
+				if has_normals {
+					normal := all_normals[corner.normal]
+					normal.Normalise()
+					normals = append(normals, normal)
+				}
+			}
+			faces[i][j] = index
+		}
+	}
+	return l.makeMesh(positions, normals, uvs, nil, faces, m)
+}
@@ -0,0 +1,161 @@
+package meshfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A unit cube made of quads, the top in its own group and material
+const test_obj = `mtllib cube.mtl
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 0 0 1
+v 1 0 1
+v 1 1 1
+v 0 1 1
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+vn 0 0 -1
+vn 0 0 1
+vn 0 -1 0
+vn 0 1 0
+vn -1 0 0
+vn 1 0 0
+
+g sides
+usemtl red_plastic
+f 1/1/1 4/4/1 3/3/1 2/2/1
+f 5/1/2 6/2/2 7/3/2 8/4/2
+f 1/1/3 2/2/3 6/3/3 5/4/3
+f 1/1/5 5/2/5 8/3/5 4/4/5
+f 2/1/6 3/2/6 7/3/6 6/4/6
+g top
+usemtl glass
+f -5/1/-3 -1/2/-3 -2/3/-3 -6/4/-3
+`
+
+func TestReadOBJ(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cube.mtl"), []byte(test_mtl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	model, err := ReadOBJ(strings.NewReader(test_obj), dir)
+	if err != nil {
+		t.Fatalf("ReadOBJ() error = %v", err)
+	}
+	if len(model.Groups) != 2 {
+		t.Fatalf("ReadOBJ() read %d groups, want 2", len(model.Groups))
+	}
+	sides, top := model.Groups[0], model.Groups[1]
+	if sides.Name != "sides" || sides.Material != "red_plastic" || top.Name != "top" || top.Material != "glass" {
+		t.Errorf("Groups = %v/%v and %v/%v", sides.Name, sides.Material, top.Name, top.Material)
+	}
+	// Quads are split in two
+	if len(sides.Mesh.Faces) != 10 || len(top.Mesh.Faces) != 2 {
+		t.Errorf("Sides and top have %d and %d triangles, want 10 and 2", len(sides.Mesh.Faces), len(top.Mesh.Faces))
+	}
+	// Positions are shared, but not when the normals differ
+	if len(top.Mesh.Positions) != 4 {
+		t.Errorf("Top has %d vertices, want 4", len(top.Mesh.Positions))
+	}
+	if top.Mesh.GetMaterial().Refractive_index != 1.5 {
+		t.Errorf("Top material IOR = %v, want the glass from the library", top.Mesh.GetMaterial().Refractive_index)
+	}
+
+	// A ray straight down through the cube goes in the top and out the bottom
+	down := rays.MakeRay(&vectors.Vector{X: 0.5, Y: 2.0, Z: 0.5}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})
+	if got := top.Mesh.CollideDistances(down); !utils.Slice_close_enough(got, []float64{1.0}) {
+		t.Errorf("Top.CollideDistances() = %v, want [1]", got)
+	}
+	if got := sides.Mesh.CollideDistances(down); !utils.Slice_close_enough(got, []float64{2.0}) {
+		t.Errorf("Sides.CollideDistances() = %v, want [2]", got)
+	}
+	if got := top.Mesh.Normal(&vectors.Vector{X: 0.5, Y: 1.0, Z: 0.5}); !got.CloseTo(&vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}) {
+		t.Errorf("Top.Normal() = %v, want up", got)
+	}
+	if got := top.Mesh.UV(&vectors.Vector{X: 0.25, Y: 1.0, Z: 0.5}); !utils.Close_enough(got.U, 0.5) || !utils.Close_enough(got.V, 0.25) {
+		t.Errorf("Top.UV() = %v, want {0.5 0.25}", got)
+	}
+	if got := len(model.Objects()); got != 2 {
+		t.Errorf("Model.Objects() has %d objects, want 2", got)
+	}
+}
+
+func TestReadOBJ_NoMaterials(t *testing.T) {
+	// Positions only, a pentagon, and no material library
+	obj := "v 0 0 0\nv 2 0 0\nv 2 1 0\nv 1 2 0\nv 0 1 0\nf 1 2 3 4 5\n"
+	model, err := ReadOBJ(strings.NewReader(obj), ".")
+	if err != nil {
+		t.Fatalf("ReadOBJ() error = %v", err)
+	}
+	mesh := model.Groups[0].Mesh
+	if len(mesh.Faces) != 3 || mesh.Normals != nil || mesh.UVs != nil {
+		t.Errorf("Mesh has %d faces, normals %v and UVs %v, want 3 faces and neither", len(mesh.Faces), mesh.Normals, mesh.UVs)
+	}
+	if mesh.GetMaterial() != DefaultMaterial() {
+		t.Errorf("Mesh material = %v, want the default", mesh.GetMaterial())
+	}
+}
+
+func TestLoader_ReadOBJ(t *testing.T) {
+	// With a cache, each mesh's BVH is saved the first time and loaded
+	// after that, and the model is the same as without one
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cube.mtl"), []byte(test_mtl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want, err := ReadOBJ(strings.NewReader(test_obj), dir)
+	if err != nil {
+		t.Fatalf("ReadOBJ() error = %v", err)
+	}
+	loader := Loader{Cache: &objects.AcceleratorCache{Dir: filepath.Join(dir, "cache")}}
+	for i := 0; i < 2; i++ {
+		got, err := loader.ReadOBJ(strings.NewReader(test_obj), dir)
+		if err != nil {
+			t.Fatalf("Loader.ReadOBJ() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Loader.ReadOBJ() = a different model from ReadOBJ() on try %d", i+1)
+		}
+	}
+	if entries, _ := os.ReadDir(loader.Cache.Dir); len(entries) != 2 {
+		t.Errorf("Loader.ReadOBJ() cached %d BVHs, want 2", len(entries))
+	}
+
+	// A cache which can't be written to
+	loader.Cache.Dir = filepath.Join(dir, "cube.mtl")
+	if _, err := loader.ReadOBJ(strings.NewReader(test_obj), dir); err == nil {
+		t.Errorf("Loader.ReadOBJ() didn't say it couldn't save to the cache")
+	}
+}
+
+func TestReadOBJ_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  string
+	}{
+		{name: "Index out of range", obj: "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 4\n"},
+		{name: "Too few vertices", obj: "v 0 0 0\nv 1 0 0\nf 1 2\n"},
+		{name: "Bad coordinate", obj: "v 0 zero 0\n"},
+		{name: "Missing library", obj: "mtllib nowhere.mtl\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadOBJ(strings.NewReader(tt.obj), t.TempDir()); err == nil {
+				t.Errorf("ReadOBJ() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package meshfiles
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func LoadPLY(path string) (Model, error) {
+	return Loader{}.LoadPLY(path)
+}
+
+func (l Loader) LoadPLY(path string) (Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Model{}, err
+	}
+	defer f.Close()
+	return l.ReadPLY(f)
+}
+
+// ReadPLY reads a Stanford PLY file, in ASCII or binary of either byte
+// order. Vertices keep their normals (nx, ny, nz), texture coordinates
+// (u, v or s, t) and colours (red, green, blue, alpha) when the file has
+// them, and the colours are used in place of the material's when shading.
+// Faces with more than three vertices are split into a fan of triangles.
+// Elements other than vertex and face are skipped.
+func ReadPLY(r io.Reader) (Model, error) {
+	return Loader{}.ReadPLY(r)
+}
+
+func (l Loader) ReadPLY(r io.Reader) (Model, error) {
+	reader := bufio.NewReader(r)
+	elements, format, err := readPLYHeader(reader)
+	if err != nil {
+		return Model{}, err
+	}
+	var values plyValues
+	switch format {
+	case "ascii":
+		scanner := bufio.NewScanner(reader)
+		scanner.Split(bufio.ScanWords)
+		values = &plyASCII{scanner: scanner}
+	case "binary_little_endian":
+		values = &plyBinary{r: reader, order: binary.LittleEndian}
+	case "binary_big_endian":
+		values = &plyBinary{r: reader, order: binary.BigEndian}
+	default:
+		return Model{}, fmt.Errorf("ply: unknown format %q", format)
+	}
+
+	if !columnsFor(elements, "vertex").has("x", "y", "z") {
+		return Model{}, fmt.Errorf("ply: vertices need x, y and z")
+	}
+
+	var positions []vectors.Vector
+	var normals []vectors.Vector
+	var uvs []objects.UV
+	var colors []color.RGBA
+	var faces [][3]int
+	for _, element := range elements {
+		columns := element.columns()
+		face_column, is_face := columns["vertex_indices"]
+		is_face = is_face && element.name == "face"
+		row := make([]float64, len(element.properties))
+		var list []float64
+		for i := 0; i < element.count; i++ {
+			for j, property := range element.properties {
+				if property.count_kind == "" {
+					row[j], err = values.next(property.kind)
+				} else {
+					list, err = readPLYList(values, property, list[:0])
+					if is_face && j == face_column {
+						faces = appendPLYFace(faces, list)
+					}
+				}
+				if err != nil {
+					return Model{}, fmt.Errorf("ply %s %d: %v", element.name, i, err)
+				}
+			}
+			if element.name != "vertex" {
+				continue
+			}
+			positions = append(positions, vectors.Vector{X: row[columns["x"]], Y: row[columns["y"]], Z: row[columns["z"]]})
+			if columns.has("nx", "ny", "nz") {
+				normal := vectors.Vector{X: row[columns["nx"]], Y: row[columns["ny"]], Z: row[columns["nz"]]}
+				normal.Normalise()
+				normals = append(normals, normal)
+			}
+			if columns.has("u", "v") {
+				uvs = append(uvs, objects.UV{U: row[columns["u"]], V: row[columns["v"]]})
+			}
+			if columns.has("red", "green", "blue") {
+				channel := func(name string) uint8 {
+					return plyColourChannel(row[columns[name]], element.properties[columns[name]].kind)
+				}
+				colour := color.RGBA{channel("red"), channel("green"), channel("blue"), 0xff}
+				if columns.has("alpha") {
+					colour.A = channel("alpha")
+				}
+				colors = append(colors, colour)
+			}
+		}
+	}
+
+	for _, face := range faces {
+		for _, index := range face {
+			if index < 0 || index >= len(positions) {
+				return Model{}, fmt.Errorf("ply: face refers to vertex %d, there are only %d", index, len(positions))
+			}
+		}
+	}
+	mesh, err := l.makeMesh(positions, normals, uvs, colors, faces, DefaultMaterial())
+	if err != nil {
+		return Model{}, err
+	}
+	return Model{Groups: []Group{{Name: "default", Mesh: mesh}}}, nil
+}
+
+type plyProperty struct {
+	name       string
+	kind       string
+	count_kind string // Only set for lists
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// Where each property we care about is in an element's rows
+type plyColumns map[string]int
+
+func (c plyColumns) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := c[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (e plyElement) columns() plyColumns {
+	// Also maps the other common names for texture coordinates and face indices onto one name.
+	aliases := map[string]string{
+		"s":             "u",
+		"t":             "v",
+		"texture_u":     "u",
+		"texture_v":     "v",
+		"texture_s":     "u",
+		"texture_t":     "v",
+		"vertex_index":  "vertex_indices",
+		"diffuse_red":   "red",
+		"diffuse_green": "green",
+		"diffuse_blue":  "blue",
+	}
+	columns := make(plyColumns)
+	for i, property := range e.properties {
+		name := property.name
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	return columns
+}
+
+func columnsFor(elements []plyElement, name string) plyColumns {
+	for _, element := range elements {
+		if element.name == name {
+			return element.columns()
+		}
+	}
+	return plyColumns{}
+}
+
+var plySizes = map[string]int{
+	"char": 1, "int8": 1, "uchar": 1, "uint8": 1,
+	"short": 2, "int16": 2, "ushort": 2, "uint16": 2,
+	"int": 4, "int32": 4, "uint": 4, "uint32": 4,
+	"float": 4, "float32": 4, "double": 8, "float64": 8,
+}
+
+func readPLYHeader(r *bufio.Reader) ([]plyElement, string, error) {
+	var elements []plyElement
+	format := ""
+	for line_number := 1; ; line_number++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, "", fmt.Errorf("ply header: %v", err)
+		}
+		fields := strings.Fields(line)
+		if line_number == 1 {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return nil, "", fmt.Errorf("not a ply file")
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		bad := fmt.Errorf("ply header line %d: can't read %q", line_number, strings.TrimSpace(line))
+		switch fields[0] {
+		case "format":
+			if len(fields) != 3 {
+				return nil, "", bad
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				return nil, "", bad
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, "", bad
+			}
+			elements = append(elements, plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return nil, "", bad
+			}
+			var property plyProperty
+			switch {
+			case len(fields) == 3:
+				property = plyProperty{name: fields[2], kind: fields[1]}
+			case len(fields) == 5 && fields[1] == "list":
+				property = plyProperty{name: fields[4], kind: fields[3], count_kind: fields[2]}
+			default:
+				return nil, "", bad
+			}
+			if _, ok := plySizes[property.kind]; !ok {
+				return nil, "", bad
+			}
+			if _, ok := plySizes[property.count_kind]; property.count_kind != "" && !ok {
+				return nil, "", bad
+			}
+			last := &elements[len(elements)-1]
+			last.properties = append(last.properties, property)
+		case "end_header":
+			return elements, format, nil
+		}
+	}
+}
+
+func readPLYList(values plyValues, property plyProperty, list []float64) ([]float64, error) {
+	n, err := values.next(property.count_kind)
+	if err != nil {
+		return list, err
+	}
+	if n < 0 || n != math.Trunc(n) {
+		return list, fmt.Errorf("bad list length %v", n)
+	}
+	for i := 0; i < int(n); i++ {
+		value, err := values.next(property.kind)
+		if err != nil {
+			return list, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+func appendPLYFace(faces [][3]int, indices []float64) [][3]int {
+	// A fan around the first vertex, as for OBJ faces.
+	for i := 1; i+1 < len(indices); i++ {
+		faces = append(faces, [3]int{int(indices[0]), int(indices[i]), int(indices[i+1])})
+	}
+	return faces
+}
+
+func plyColourChannel(value float64, kind string) uint8 {
+	// Colours are usually bytes, but some files store them as floats in [0, 1].
+	if kind == "float" || kind == "float32" || kind == "double" || kind == "float64" {
+		value *= 255.0
+	}
+	return uint8(math.Round(math.Max(0.0, math.Min(255.0, value))))
+}
+
+// Reads a ply file's values one at a time, whatever its format
+type plyValues interface {
+	next(kind string) (float64, error)
+}
+
+type plyASCII struct {
+	scanner *bufio.Scanner
+}
+
+func (p *plyASCII) next(kind string) (float64, error) {
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return 0.0, err
+		}
+		return 0.0, io.ErrUnexpectedEOF
+	}
+	return strconv.ParseFloat(p.scanner.Text(), 64)
+}
+
+type plyBinary struct {
+	r     io.Reader
+	order binary.ByteOrder
+	buf   [8]byte
+}
+
+func (p *plyBinary) next(kind string) (float64, error) {
+	b := p.buf[:plySizes[kind]]
+	if _, err := io.ReadFull(p.r, b); err != nil {
+		return 0.0, err
+	}
+	switch kind {
+	case "char", "int8":
+		return float64(int8(b[0])), nil
+	case "uchar", "uint8":
+		return float64(b[0]), nil
+	case "short", "int16":
+		return float64(int16(p.order.Uint16(b))), nil
+	case "ushort", "uint16":
+		return float64(p.order.Uint16(b)), nil
+	case "int", "int32":
+		return float64(int32(p.order.Uint32(b))), nil
+	case "uint", "uint32":
+		return float64(p.order.Uint32(b)), nil
+	case "float", "float32":
+		return float64(math.Float32frombits(p.order.Uint32(b))), nil
+	default:
+		return math.Float64frombits(p.order.Uint64(b)), nil
+	}
+}
@@ -0,0 +1,134 @@
+package meshfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A unit square in the z = 0 plane facing -z, red along x = 0 and blue along x = 1
+const test_ply = `ply
+format ascii 1.0
+comment made by hand
+element vertex 4
+property float x
+property float y
+property float z
+property float nx
+property float ny
+property float nz
+property uchar red
+property uchar green
+property uchar blue
+element face 1
+property list uchar int vertex_indices
+element note 1
+property int id
+end_header
+0 0 0 0 0 -2 255 0 0
+1 0 0 0 0 -2 0 0 255
+1 1 0 0 0 -2 0 0 255
+0 1 0 0 0 -2 255 0 0
+4 0 3 2 1
+7
+`
+
+func binaryTestPLY(order binary.ByteOrder, format string) []byte {
+	// The same square as test_ply, in binary.
+	var b bytes.Buffer
+	b.WriteString(strings.Replace(test_ply[:strings.Index(test_ply, "end_header\n")+len("end_header\n")], "ascii", format, 1))
+	vertices := [][6]float32{{0, 0, 0, 0, 0, -2}, {1, 0, 0, 0, 0, -2}, {1, 1, 0, 0, 0, -2}, {0, 1, 0, 0, 0, -2}}
+	colours := [][3]uint8{{255, 0, 0}, {0, 0, 255}, {0, 0, 255}, {255, 0, 0}}
+	for i := range vertices {
+		binary.Write(&b, order, vertices[i])
+		binary.Write(&b, order, colours[i])
+	}
+	binary.Write(&b, order, uint8(4))
+	binary.Write(&b, order, []int32{0, 3, 2, 1})
+	binary.Write(&b, order, int32(7))
+	return b.Bytes()
+}
+
+func TestReadPLY(t *testing.T) {
+	tests := []struct {
+		name string
+		ply  []byte
+	}{
+		{name: "ASCII", ply: []byte(test_ply)},
+		{name: "Binary little endian", ply: binaryTestPLY(binary.LittleEndian, "binary_little_endian")},
+		{name: "Binary big endian", ply: binaryTestPLY(binary.BigEndian, "binary_big_endian")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := ReadPLY(bytes.NewReader(tt.ply))
+			if err != nil {
+				t.Fatalf("ReadPLY() error = %v", err)
+			}
+			mesh := model.Groups[0].Mesh
+			if len(mesh.Positions) != 4 || len(mesh.Faces) != 2 {
+				t.Fatalf("Mesh has %d vertices and %d faces, want 4 and 2", len(mesh.Positions), len(mesh.Faces))
+			}
+			if !mesh.Positions[2].CloseTo(&vectors.Vector{X: 1.0, Y: 1.0, Z: 0.0}) {
+				t.Errorf("Third vertex = %v, want {1 1 0}", mesh.Positions[2])
+			}
+
+			ray := rays.MakeRay(&vectors.Vector{X: 0.25, Y: 0.5, Z: -1.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})
+			if got := mesh.CollideDistances(ray); !utils.Slice_close_enough(got, []float64{1.0}) {
+				t.Errorf("Mesh.CollideDistances() = %v, want [1]", got)
+			}
+			point := &vectors.Vector{X: 0.25, Y: 0.5, Z: 0.0}
+			if got := mesh.Normal(point); !got.CloseTo(&vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}) {
+				t.Errorf("Mesh.Normal() = %v, want the file's normal {0 0 -1}", got)
+			}
+			// A quarter of the way from red to blue
+			want := color.RGBA{191, 0, 64, 0xff}
+			if got := mesh.MaterialAt(point).Color; got != want {
+				t.Errorf("Mesh.MaterialAt().Color = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadPLY_FloatColours(t *testing.T) {
+	ply := "ply\nformat ascii 1.0\nelement vertex 3\nproperty double x\nproperty double y\nproperty double z\n" +
+		"property float red\nproperty float green\nproperty float blue\nproperty float alpha\n" +
+		"element face 1\nproperty list uchar uint vertex_index\nend_header\n" +
+		"0 0 0 1 0.5 0 1\n1 0 0 1 0.5 0 1\n0 1 0 1 0.5 0 1\n3 0 1 2\n"
+	model, err := ReadPLY(strings.NewReader(ply))
+	if err != nil {
+		t.Fatalf("ReadPLY() error = %v", err)
+	}
+	mesh := model.Groups[0].Mesh
+	want := color.RGBA{255, 128, 0, 255}
+	if mesh.Colors[0] != want || mesh.Normals != nil {
+		t.Errorf("Mesh colour = %v and normals %v, want %v and none", mesh.Colors[0], mesh.Normals, want)
+	}
+}
+
+func TestReadPLY_Errors(t *testing.T) {
+	header := "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n"
+	tests := []struct {
+		name string
+		ply  string
+	}{
+		{name: "Not a ply file", ply: "solid cube\n"},
+		{name: "Unknown format", ply: strings.Replace(header, "ascii", "binary_middle_endian", 1) + "0 0 0\n3 0 0 0\n"},
+		{name: "Unknown type", ply: strings.Replace(header, "float x", "quad x", 1)},
+		{name: "No positions", ply: strings.Replace(header, "property float z\n", "", 1)},
+		{name: "Too short", ply: header + "0 0 0\n3 0 0\n"},
+		{name: "Index out of range", ply: header + "0 0 0\n3 0 0 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadPLY(strings.NewReader(tt.ply)); err == nil {
+				t.Errorf("ReadPLY() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package meshfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func LoadSTL(path string, smooth bool) (Model, error) {
+	return Loader{}.LoadSTL(path, smooth)
+}
+
+func (l Loader) LoadSTL(path string, smooth bool) (Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Model{}, err
+	}
+	defer f.Close()
+	return l.ReadSTL(f, smooth)
+}
+
+// ReadSTL reads an ASCII or binary STL file. STL stores each facet's corners
+// separately along with a facet normal, which is used for shading as it is,
+// or worked out from the winding if the file leaves it as zero. With smooth
+// set, corners at the same position are joined up and get the area weighted
+// average normal of the facets around them instead. Each solid in an ASCII
+// file becomes its own group.
+func ReadSTL(r io.Reader, smooth bool) (Model, error) {
+	return Loader{}.ReadSTL(r, smooth)
+}
+
+func (l Loader) ReadSTL(r io.Reader, smooth bool) (Model, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Model{}, err
+	}
+	// ASCII files start with "solid", but so do some binary ones, so trust
+	// the binary facet count first.
+	var solids []stlSolid
+	if len(data) >= 84 && 84+50*int(binary.LittleEndian.Uint32(data[80:84])) == len(data) {
+		solids, err = readBinarySTL(data)
+	} else if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		solids, err = readASCIISTL(string(data))
+	} else {
+		err = fmt.Errorf("not an stl file")
+	}
+	if err != nil {
+		return Model{}, err
+	}
+
+	var model Model
+	for _, solid := range solids {
+		mesh, err := solid.mesh(l, smooth)
+		if err != nil {
+			return Model{}, err
+		}
+		model.Groups = append(model.Groups, Group{Name: solid.name, Mesh: mesh})
+	}
+	return model, nil
+}
+
+type stlFacet struct {
+	normal  vectors.Vector
+	corners [3]vectors.Vector
+}
+
+type stlSolid struct {
+	name   string
+	facets []stlFacet
+}
+
+func (s stlSolid) mesh(l Loader, smooth bool) (*objects.Mesh, error) {
+	var positions []vectors.Vector
+	var normals []vectors.Vector
+	faces := make([][3]int, len(s.facets))
+	vertex_indices := make(map[vectors.Vector]int)
+	for i, facet := range s.facets {
+		// Twice the facet's area, along the normal from its winding
+		winding := facet.corners[1].Subtract(&facet.corners[0]).Cross(facet.corners[2].Subtract(&facet.corners[0]))
+		normal := facet.normal
+		normal.Normalise()
+		if normal.Magnitude() == 0.0 {
+			normal = *winding
+			normal.Normalise()
+		}
+		for j, corner := range facet.corners {
+			if !smooth {
+				faces[i][j] = len(positions)
+				positions = append(positions, corner)
+				normals = append(normals, normal)
+				continue
+			}
+			index, ok := vertex_indices[corner]
+			if !ok {
+				index = len(positions)
+				vertex_indices[corner] = index
+				positions = append(positions, corner)
+				normals = append(normals, vectors.Vector{})
+			}
+			normals[index] = *normals[index].Add(normal.MultiplyScalar(winding.Magnitude()))
+			faces[i][j] = index
+		}
+	}
+	for i := range normals {
+		normals[i].Normalise()
+	}
+	return l.makeMesh(positions, normals, nil, nil, faces, DefaultMaterial())
+}
+
+func readBinarySTL(data []byte) ([]stlSolid, error) {
+	// An 80 byte header, a facet count, then 50 bytes per facet: 12 little
+	// endian float32s and two bytes of attributes, which we skip.
+	count := int(binary.LittleEndian.Uint32(data[80:84]))
+	solid := stlSolid{name: "default", facets: make([]stlFacet, count)}
+	read := func(offset int) vectors.Vector {
+		var xyz [3]float64
+		for i := range xyz {
+			xyz[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+4*i:])))
+		}
+		return vectors.Vector{X: xyz[0], Y: xyz[1], Z: xyz[2]}
+	}
+	for i := range solid.facets {
+		offset := 84 + 50*i
+		solid.facets[i].normal = read(offset)
+		for j := range solid.facets[i].corners {
+			solid.facets[i].corners[j] = read(offset + 12*(j+1))
+		}
+	}
+	return []stlSolid{solid}, nil
+}
+
+func readASCIISTL(text string) ([]stlSolid, error) {
+	var solids []stlSolid
+	var solid *stlSolid
+	var facet stlFacet
+	corners := 0
+	for line_number, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "solid":
+			name := strings.Join(fields[1:], " ")
+			if name == "" {
+				name = "default"
+			}
+			solids = append(solids, stlSolid{name: name})
+			solid = &solids[len(solids)-1]
+		case "facet":
+			if solid == nil || len(fields) != 5 || fields[1] != "normal" {
+				err = fmt.Errorf("bad facet")
+				break
+			}
+			var xyz [3]float64
+			xyz, err = parseFloats3(fields[2:])
+			facet = stlFacet{normal: vectors.Vector{X: xyz[0], Y: xyz[1], Z: xyz[2]}}
+			corners = 0
+		case "vertex":
+			if corners >= 3 || len(fields) != 4 {
+				err = fmt.Errorf("bad vertex")
+				break
+			}
+			var xyz [3]float64
+			xyz, err = parseFloats3(fields[1:])
+			facet.corners[corners] = vectors.Vector{X: xyz[0], Y: xyz[1], Z: xyz[2]}
+			corners++
+		case "endfacet":
+			if solid == nil || corners != 3 {
+				err = fmt.Errorf("a facet needs exactly 3 vertices")
+				break
+			}
+			solid.facets = append(solid.facets, facet)
+		case "outer", "endloop", "endsolid":
+		default:
+			err = fmt.Errorf("unknown keyword %q", fields[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stl line %d: %v", line_number+1, err)
+		}
+	}
+	return solids, nil
+}
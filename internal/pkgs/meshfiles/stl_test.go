@@ -0,0 +1,106 @@
+package meshfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Two facets of a tent, meeting along the ridge x = 0, y = 1. The second
+// facet's normal is left for the reader to work out.
+const test_stl = `solid tent
+  facet normal -1 1 0
+    outer loop
+      vertex -1 0 0
+      vertex 0 1 1
+      vertex 0 1 0
+    endloop
+  endfacet
+  facet normal 0 0 0
+    outer loop
+      vertex 1 0 0
+      vertex 0 1 0
+      vertex 0 1 1
+    endloop
+  endfacet
+endsolid tent
+`
+
+func binaryTestSTL() []byte {
+	// The same tent as test_stl, with a header that looks like ASCII.
+	var b bytes.Buffer
+	header := make([]byte, 80)
+	copy(header, "solid tent, but binary")
+	b.Write(header)
+	binary.Write(&b, binary.LittleEndian, uint32(2))
+	facets := [][12]float32{
+		{-1, 1, 0, -1, 0, 0, 0, 1, 1, 0, 1, 0},
+		{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 1, 1},
+	}
+	for _, facet := range facets {
+		binary.Write(&b, binary.LittleEndian, facet)
+		binary.Write(&b, binary.LittleEndian, uint16(0))
+	}
+	return b.Bytes()
+}
+
+func TestReadSTL(t *testing.T) {
+	left := &vectors.Vector{X: -0.70710678, Y: 0.70710678, Z: 0.0}
+	right := &vectors.Vector{X: 0.70710678, Y: 0.70710678, Z: 0.0}
+	up := &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+	tests := []struct {
+		name          string
+		stl           []byte
+		smooth        bool
+		wantName      string
+		wantPositions int
+		wantLeft      *vectors.Vector
+		wantRidge     *vectors.Vector
+	}{
+		{name: "ASCII facets", stl: []byte(test_stl), wantName: "tent", wantPositions: 6, wantLeft: left, wantRidge: right},
+		{name: "ASCII smooth", stl: []byte(test_stl), smooth: true, wantName: "tent", wantPositions: 4, wantLeft: left, wantRidge: up},
+		{name: "Binary facets", stl: binaryTestSTL(), wantName: "default", wantPositions: 6, wantLeft: left, wantRidge: right},
+		{name: "Binary smooth", stl: binaryTestSTL(), smooth: true, wantName: "default", wantPositions: 4, wantLeft: left, wantRidge: up},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := ReadSTL(bytes.NewReader(tt.stl), tt.smooth)
+			if err != nil {
+				t.Fatalf("ReadSTL() error = %v", err)
+			}
+			group := model.Groups[0]
+			if group.Name != tt.wantName || len(group.Mesh.Positions) != tt.wantPositions || len(group.Mesh.Faces) != 2 {
+				t.Fatalf("Group %q has %d vertices and %d faces, want %q, %d and 2", group.Name, len(group.Mesh.Positions), len(group.Mesh.Faces), tt.wantName, tt.wantPositions)
+			}
+			if got := group.Mesh.Normal(&vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0}); !got.CloseTo(tt.wantLeft) {
+				t.Errorf("Mesh.Normal() at the left foot = %v, want %v", got, tt.wantLeft)
+			}
+			// Just on the right of the ridge
+			if got := group.Mesh.Normal(&vectors.Vector{X: 1e-9, Y: 1.0 - 1e-9, Z: 0.5}); !got.CloseTo(tt.wantRidge) {
+				t.Errorf("Mesh.Normal() at the ridge = %v, want %v", got, tt.wantRidge)
+			}
+		})
+	}
+}
+
+func TestReadSTL_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		stl  string
+	}{
+		{name: "Not an stl file", stl: "ply\n"},
+		{name: "Too few vertices", stl: "solid a\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nendloop\nendfacet\nendsolid a\n"},
+		{name: "Bad coordinate", stl: "solid a\nfacet normal 0 0 1\nouter loop\nvertex 0 zero 0\n"},
+		{name: "Unknown keyword", stl: "solid a\nfacet normal 0 0 1\nouter lop\nvertices 0 0 0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadSTL(strings.NewReader(tt.stl), false); err == nil {
+				t.Errorf("ReadSTL() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package objects
+
+import (
+	"log"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+)
+
+// Accelerators find which of many primitives a ray might hit, so it doesn't
+// have to be tested against all of them. They're built from the primitives'
+// bounds, and refer to primitives by their index in those.
+type Accelerator interface {
+	// Visits the primitives the ray might hit between t_min and t_max,
+	// roughly nearest first, until visit says to stop. Some accelerators
+	// visit a primitive more than once.
+	Traverse(test_ray rays.Ray, t_min float64, t_max float64, visit Visitor)
+}
+
+// Called by Traverse with each primitive the ray might hit, and the
+// furthest distance still of interest. It returns that distance, reduced
+// if it found a hit and only nearer ones matter now, and whether to stop.
+type Visitor func(primitive int, t_max float64) (float64, bool)
+
+type AcceleratorKind int
+
+const (
+	BVHAccelerator    AcceleratorKind = iota // Best all round
+	GridAccelerator                          // Best for many primitives of about the same size, spread evenly
+	KDTreeAccelerator                        // Best for primitives of very different sizes
+)
+
+func BuildAccelerator(kind AcceleratorKind, bounds []AABB) Accelerator {
+	switch kind {
+	case BVHAccelerator:
+		return BuildBVH(bounds)
+	case GridAccelerator:
+		return BuildGrid(bounds)
+	case KDTreeAccelerator:
+		return BuildKDTree(bounds)
+	}
+	log.Fatalf("Unknown accelerator kind %v.", kind)
+	return nil
+}
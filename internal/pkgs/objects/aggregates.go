@@ -0,0 +1,88 @@
+package objects
+
+import (
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+)
+
+func MakeAggregate(objs []Object) *Aggregate {
+	return MakeAggregateUsing(objs, BVHAccelerator)
+}
+
+func MakeAggregateUsing(objs []Object, kind AcceleratorKind) *Aggregate {
+	// Which accelerator is fastest depends on how the objects are laid out.
+	a, bounds := splitBounded(objs)
+	a.accelerator = BuildAccelerator(kind, bounds)
+	return a
+}
+
+func MakeAggregateCached(objs []Object, kind AcceleratorKind, cache AcceleratorCache) (*Aggregate, error) {
+	// As MakeAggregateUsing, but the accelerator comes from cache when the
+	// same bounds have been seen before, and is saved there otherwise. The
+	// aggregate is always usable; the error only says it couldn't be saved.
+	a, bounds := splitBounded(objs)
+	var err error
+	a.accelerator, err = cache.Build(kind, bounds)
+	return a, err
+}
+
+func splitBounded(objs []Object) (*Aggregate, []AABB) {
+	// An aggregate without its accelerator yet, and the bounds to build it from.
+	a := &Aggregate{}
+	var bounds []AABB
+	for _, obj := range objs {
+		if b, ok := BoundsOf(obj); ok {
+			a.bounded = append(a.bounded, obj)
+			bounds = append(bounds, b)
+		} else {
+			a.unbounded = append(a.unbounded, obj)
+		}
+	}
+	return a, bounds
+}
+
+// Aggregate finds what rays hit among many objects, without testing them
+// all. Objects with bounds go in an accelerator; the likes of planes, which
+// would make it infinitely big, are kept to one side and always tested.
+type Aggregate struct {
+	bounded     []Object
+	unbounded   []Object
+	accelerator Accelerator
+}
+
+func (a *Aggregate) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// The nearest hit on any of the objects.
+	var closest Hit
+	found := false
+	for _, obj := range a.unbounded {
+		if hit, ok := Intersect(obj, test_ray, t_min, t_max); ok {
+			closest, t_max, found = hit, hit.T, true
+		}
+	}
+	a.accelerator.Traverse(test_ray, t_min, t_max, func(i int, t_max float64) (float64, bool) {
+		if hit, ok := Intersect(a.bounded[i], test_ray, t_min, t_max); ok {
+			closest, found = hit, true
+			return hit.T, false
+		}
+		return t_max, false
+	})
+	return closest, found
+}
+
+func (a *Aggregate) Occluded(test_ray rays.Ray, t_min float64, t_max float64) (Object, bool) {
+	// The first object found in the way of the ray between t_min and t_max,
+	// not necessarily the nearest, so it can stop looking straight away.
+	for _, obj := range a.unbounded {
+		if Occluded(obj, test_ray, t_min, t_max) {
+			return obj, true
+		}
+	}
+	var blocker Object
+	a.accelerator.Traverse(test_ray, t_min, t_max, func(i int, t_max float64) (float64, bool) {
+		if Occluded(a.bounded[i], test_ray, t_min, t_max) {
+			blocker = a.bounded[i]
+			return t_max, true
+		}
+		return t_max, false
+	})
+	return blocker, blocker != nil
+}
@@ -0,0 +1,209 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// An axis-aligned bounding box
+type AABB struct {
+	Min vectors.Vector
+	Max vectors.Vector
+}
+
+// Objects which fit inside a box, which is everything but the likes of
+// planes. Some only know whether they do once they're built, e.g. a
+// transformed plane isn't bounded, so they say with ok.
+type Bounded interface {
+	BoundingBox() (bounds AABB, ok bool)
+}
+
+func EmptyAABB() AABB {
+	// Contains nothing, and unioning with it changes nothing.
+	inf := math.Inf(1)
+	return AABB{
+		Min: vectors.Vector{X: inf, Y: inf, Z: inf},
+		Max: vectors.Vector{X: -inf, Y: -inf, Z: -inf},
+	}
+}
+
+func BoundsOf(obj Object) (AABB, bool) {
+	if bounded, ok := obj.(Bounded); ok {
+		return bounded.BoundingBox()
+	}
+	return AABB{}, false
+}
+
+func (b AABB) Union(c AABB) AABB {
+	return AABB{
+		Min: vectors.Vector{X: math.Min(b.Min.X, c.Min.X), Y: math.Min(b.Min.Y, c.Min.Y), Z: math.Min(b.Min.Z, c.Min.Z)},
+		Max: vectors.Vector{X: math.Max(b.Max.X, c.Max.X), Y: math.Max(b.Max.Y, c.Max.Y), Z: math.Max(b.Max.Z, c.Max.Z)},
+	}
+}
+
+func (b AABB) Intersect(c AABB) AABB {
+	return AABB{
+		Min: vectors.Vector{X: math.Max(b.Min.X, c.Min.X), Y: math.Max(b.Min.Y, c.Min.Y), Z: math.Max(b.Min.Z, c.Min.Z)},
+		Max: vectors.Vector{X: math.Min(b.Max.X, c.Max.X), Y: math.Min(b.Max.Y, c.Max.Y), Z: math.Min(b.Max.Z, c.Max.Z)},
+	}
+}
+
+func (b AABB) Overlaps(c AABB) bool {
+	// Touching counts.
+	return b.Min.X <= c.Max.X && b.Max.X >= c.Min.X &&
+		b.Min.Y <= c.Max.Y && b.Max.Y >= c.Min.Y &&
+		b.Min.Z <= c.Max.Z && b.Max.Z >= c.Min.Z
+}
+
+func (b AABB) AddPoint(point *vectors.Vector) AABB {
+	return b.Union(AABB{Min: *point, Max: *point})
+}
+
+func (b AABB) Expand(by *vectors.Vector) AABB {
+	// Grown by by on each side.
+	return AABB{Min: *b.Min.Subtract(by), Max: *b.Max.Add(by)}
+}
+
+func (b AABB) Transform(transform *vectors.Matrix) AABB {
+	// A box around all eight corners once transformed.
+	transformed := EmptyAABB()
+	for i := 0; i < 8; i++ {
+		corner := b.Min
+		if i&1 != 0 {
+			corner.X = b.Max.X
+		}
+		if i&2 != 0 {
+			corner.Y = b.Max.Y
+		}
+		if i&4 != 0 {
+			corner.Z = b.Max.Z
+		}
+		transformed = transformed.AddPoint(transform.TransformPoint(&corner))
+	}
+	return transformed
+}
+
+func (b AABB) Hit(test_ray rays.Ray) (t_near float64, t_far float64, ok bool) {
+	// Where the ray is inside the box, by the slab method: between all
+	// three pairs of planes at once. The box may be behind the ray, so
+	// either distance can be negative.
+	origin := [3]float64{test_ray.Origin.X, test_ray.Origin.Y, test_ray.Origin.Z}
+	direction := [3]float64{test_ray.Direction.X, test_ray.Direction.Y, test_ray.Direction.Z}
+	min := [3]float64{b.Min.X, b.Min.Y, b.Min.Z}
+	max := [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+
+	t_near, t_far = math.Inf(-1), math.Inf(1)
+	for axis := 0; axis < 3; axis++ {
+		if direction[axis] == 0.0 {
+			if origin[axis] < min[axis] || origin[axis] > max[axis] {
+				return 0.0, 0.0, false
+			}
+			continue
+		}
+		t0 := (min[axis] - origin[axis]) / direction[axis]
+		t1 := (max[axis] - origin[axis]) / direction[axis]
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		t_near = math.Max(t_near, t0)
+		t_far = math.Min(t_far, t1)
+	}
+	return t_near, t_far, t_near <= t_far && t_far > 0.0
+}
+
+func discExtent(normal *vectors.Vector, radius float64) *vectors.Vector {
+	// Half the size of the box around a disc facing along normal.
+	unit := *normal
+	unit.Normalise()
+	return &vectors.Vector{
+		X: radius * math.Sqrt(math.Max(0.0, 1.0-unit.X*unit.X)),
+		Y: radius * math.Sqrt(math.Max(0.0, 1.0-unit.Y*unit.Y)),
+		Z: radius * math.Sqrt(math.Max(0.0, 1.0-unit.Z*unit.Z)),
+	}
+}
+
+func (s Sphere) BoundingBox() (AABB, bool) {
+	r := &vectors.Vector{X: s.Radius, Y: s.Radius, Z: s.Radius}
+	return AABB{Min: s.Center, Max: s.Center}.Expand(r), true
+}
+
+func (b Box) BoundingBox() (AABB, bool) {
+	return AABB{Min: b.Min, Max: b.Max}, true
+}
+
+func (c Cylinder) BoundingBox() (AABB, bool) {
+	top := c.Base.Add(c.Axis.MultiplyScalar(c.Height))
+	return AABB{Min: c.Base, Max: c.Base}.AddPoint(top).Expand(discExtent(&c.Axis, c.Radius)), true
+}
+
+func (c Cone) BoundingBox() (AABB, bool) {
+	apex := c.Base.Add(c.Axis.MultiplyScalar(c.Height))
+	return AABB{Min: c.Base, Max: c.Base}.Expand(discExtent(&c.Axis, c.Radius)).AddPoint(apex), true
+}
+
+func (d Disc) BoundingBox() (AABB, bool) {
+	return AABB{Min: d.Center, Max: d.Center}.Expand(discExtent(&d.DiscNormal, d.Radius)), true
+}
+
+func (r Rectangle) BoundingBox() (AABB, bool) {
+	far := r.Corner.Add(&r.Edge1).Add(&r.Edge2)
+	return AABB{Min: r.Corner, Max: r.Corner}.AddPoint(r.Corner.Add(&r.Edge1)).AddPoint(r.Corner.Add(&r.Edge2)).AddPoint(far), true
+}
+
+func (t Triangle) BoundingBox() (AABB, bool) {
+	b := EmptyAABB()
+	for i := range t.Vertices {
+		b = b.AddPoint(&t.Vertices[i])
+	}
+	return b, true
+}
+
+func (m *Mesh) BoundingBox() (AABB, bool) {
+	b := EmptyAABB()
+	for i := range m.Positions {
+		b = b.AddPoint(&m.Positions[i])
+	}
+	return b, len(m.Positions) > 0
+}
+
+func (t Torus) BoundingBox() (AABB, bool) {
+	ring := discExtent(&t.Axis, t.MajorRadius)
+	tube := &vectors.Vector{X: t.MinorRadius, Y: t.MinorRadius, Z: t.MinorRadius}
+	return AABB{Min: t.Center, Max: t.Center}.Expand(ring.Add(tube)), true
+}
+
+func (s Implicit) BoundingBox() (AABB, bool) {
+	if s.Bounds == nil {
+		return AABB{}, false
+	}
+	return AABB{Min: s.Bounds.Min, Max: s.Bounds.Max}, true
+}
+
+func (t Transformed) BoundingBox() (AABB, bool) {
+	b, ok := BoundsOf(t.Object)
+	if !ok {
+		return AABB{}, false
+	}
+	return b.Transform(&t.Transform), true
+}
+
+func (c CSG) BoundingBox() (AABB, bool) {
+	left, left_ok := BoundsOf(c.Left)
+	right, right_ok := BoundsOf(c.Right)
+	switch c.Operation {
+	case Intersection:
+		if left_ok && right_ok {
+			return left.Intersect(right), true
+		}
+		if left_ok {
+			return left, true
+		}
+		return right, right_ok
+	case Difference:
+		return left, left_ok
+	default:
+		return left.Union(right), left_ok && right_ok
+	}
+}
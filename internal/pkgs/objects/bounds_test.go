@@ -0,0 +1,88 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestAABB_Hit(t *testing.T) {
+	b := AABB{Min: vectors.Vector{X: -1.0, Y: -1.0, Z: 4.0}, Max: vectors.Vector{X: 1.0, Y: 1.0, Z: 6.0}}
+	tests := []struct {
+		name     string
+		ray      rays.Ray
+		wantNear float64
+		wantFar  float64
+		wantOk   bool
+	}{
+		{name: "Through", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), wantNear: 4.0, wantFar: 6.0, wantOk: true},
+		{name: "From inside", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), wantNear: -1.0, wantFar: 1.0, wantOk: true},
+		{name: "Behind", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), wantOk: false},
+		{name: "Beside", ray: rays.MakeRay(&vectors.Vector{X: 2.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			near, far, ok := b.Hit(tt.ray)
+			if ok != tt.wantOk || (ok && (near != tt.wantNear || far != tt.wantFar)) {
+				t.Errorf("AABB.Hit() = %v, %v, %v, want %v, %v, %v", near, far, ok, tt.wantNear, tt.wantFar, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestAABB_Transform(t *testing.T) {
+	b := AABB{Min: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Max: vectors.Vector{X: 2.0, Y: 1.0, Z: 1.0}}
+	got := b.Transform(vectors.MakeTranslation(&vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}).Multiply(vectors.MakeRotation(&vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}, 90.0)))
+	want := AABB{Min: vectors.Vector{X: -1.0, Y: 0.0, Z: 5.0}, Max: vectors.Vector{X: 0.0, Y: 2.0, Z: 6.0}}
+	if !got.Min.CloseTo(&want.Min) || !got.Max.CloseTo(&want.Max) {
+		t.Errorf("AABB.Transform() = %v, want %v", got, want)
+	}
+}
+
+func TestBoundsOf(t *testing.T) {
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+	origin := vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}
+	plane := Plane{PlaneNormal: up, Point: origin}
+	tests := []struct {
+		name   string
+		obj    Object
+		want   AABB
+		wantOk bool
+	}{
+		{
+			name:   "Sphere",
+			obj:    Sphere{Radius: 1.0, Center: vectors.Vector{X: 1.0, Y: 2.0, Z: 3.0}},
+			want:   AABB{Min: vectors.Vector{X: 0.0, Y: 1.0, Z: 2.0}, Max: vectors.Vector{X: 2.0, Y: 3.0, Z: 4.0}},
+			wantOk: true,
+		},
+		{
+			name:   "Upright cylinder",
+			obj:    Cylinder{Base: origin, Axis: up, Radius: 1.0, Height: 3.0},
+			want:   AABB{Min: vectors.Vector{X: -1.0, Y: 0.0, Z: -1.0}, Max: vectors.Vector{X: 1.0, Y: 3.0, Z: 1.0}},
+			wantOk: true,
+		},
+		{
+			name:   "Flat torus",
+			obj:    Torus{Center: origin, Axis: up, MajorRadius: 3.0, MinorRadius: 1.0},
+			want:   AABB{Min: vectors.Vector{X: -4.0, Y: -1.0, Z: -4.0}, Max: vectors.Vector{X: 4.0, Y: 1.0, Z: 4.0}},
+			wantOk: true,
+		},
+		{name: "Plane", obj: plane, wantOk: false},
+		{name: "Moved plane", obj: MakeTransformed(plane, vectors.MakeTranslation(&up)), wantOk: false},
+		{
+			name:   "Hemisphere",
+			obj:    CSG{Operation: Intersection, Left: Sphere{Radius: 1.0, Center: origin}, Right: plane},
+			want:   AABB{Min: vectors.Vector{X: -1.0, Y: -1.0, Z: -1.0}, Max: vectors.Vector{X: 1.0, Y: 1.0, Z: 1.0}},
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := BoundsOf(tt.obj)
+			if ok != tt.wantOk || (ok && (!got.Min.CloseTo(&tt.want.Min) || !got.Max.CloseTo(&tt.want.Max))) {
+				t.Errorf("BoundsOf() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// An axis-aligned box between the corners Min and Max
+type Box struct {
+	Min      vectors.Vector
+	Max      vectors.Vector
+	Material materials.Material
+}
+
+func (b Box) CollideDistances(test_ray rays.Ray) []float64 {
+	t_near, t_far, ok := AABB{Min: b.Min, Max: b.Max}.Hit(test_ray)
+	if !ok {
+		return nil
+	}
+	return positiveAscending([]float64{t_near, t_far})
+}
+
+func (b Box) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	t_near, t_far, ok := AABB{Min: b.Min, Max: b.Max}.Hit(test_ray)
+	if !ok {
+		return Hit{}, false
+	}
+	t := t_near
+	if t <= t_min || t >= t_max {
+		t = t_far
+		if t <= t_min || t >= t_max {
+			return Hit{}, false
+		}
+	}
+	o, d := test_ray.Origin, test_ray.Direction
+	hit := Hit{T: t, Material: b.Material}
+	hit.Point = vectors.Vector{X: o.X + t*d.X, Y: o.Y + t*d.Y, Z: o.Z + t*d.Z}
+	hit.GeometricNormal = b.normalAt(&hit.Point)
+	hit.ShadingNormal = hit.GeometricNormal
+	hit.FrontFace = d.Dot(&hit.GeometricNormal) < 0.0
+	return hit, true
+}
+
+func (b Box) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	normal := b.normalAt(surface_point)
+	return &normal
+}
+
+func (b Box) normalAt(surface_point *vectors.Vector) vectors.Vector {
+	// Points out of whichever face the point is closest to.
+	point := [3]float64{surface_point.X, surface_point.Y, surface_point.Z}
+	min := [3]float64{b.Min.X, b.Min.Y, b.Min.Z}
+	max := [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+	var normal [3]float64
+	closest := math.Inf(1)
+	for axis := 0; axis < 3; axis++ {
+		if dist := math.Abs(point[axis] - min[axis]); dist < closest {
+			closest = dist
+			normal = [3]float64{}
+			normal[axis] = -1.0
+		}
+		if dist := math.Abs(point[axis] - max[axis]); dist < closest {
+			closest = dist
+			normal = [3]float64{}
+			normal[axis] = 1.0
+		}
+	}
+	return vectors.Vector{X: normal[0], Y: normal[1], Z: normal[2]}
+}
+
+func (b Box) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, b.Normal(point_of_intersection))
+}
+
+func (b Box) GetMaterial() materials.Material {
+	return b.Material
+}
@@ -0,0 +1,80 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestBox_CollideDistances(t *testing.T) {
+	b := Box{Min: vectors.Vector{X: -1.0, Y: -1.0, Z: 4.0}, Max: vectors.Vector{X: 1.0, Y: 2.0, Z: 6.0}}
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Straight through the middle",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{4.0, 6.0},
+		},
+		{
+			name: "Diagonally in the top and out the back",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 3.0, Z: 4.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 1.0})},
+			want: []float64{1.41421356, 2.82842712},
+		},
+		{
+			name: "From inside",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{1.0},
+		},
+		{
+			name: "Parallel to a face, outside",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 3.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Pointing away",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Grazing an edge",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 2.0, Y: 0.0, Z: 5.0}, &vectors.Vector{X: -1.0, Y: 0.0, Z: -1.0})},
+			want: []float64{1.41421356},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Box.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBox_Normal(t *testing.T) {
+	b := Box{Min: vectors.Vector{X: -1.0, Y: -1.0, Z: 4.0}, Max: vectors.Vector{X: 1.0, Y: 2.0, Z: 6.0}}
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Front", point: &vectors.Vector{X: 0.2, Y: 0.5, Z: 4.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}},
+		{name: "Back", point: &vectors.Vector{X: 0.2, Y: 0.5, Z: 6.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}},
+		{name: "Top", point: &vectors.Vector{X: 0.9, Y: 2.0, Z: 5.5}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Left", point: &vectors.Vector{X: -1.0, Y: -0.9, Z: 5.0}, want: &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Box.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
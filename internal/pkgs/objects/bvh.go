@@ -0,0 +1,212 @@
+package objects
+
+import (
+	"math"
+	"sort"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+)
+
+// BVH is a bounding volume hierarchy: a tree of boxes, each around the
+// boxes below it, with the primitives (objects, or a mesh's faces) in the
+// leaves. A ray only needs testing against the primitives in the leaves
+// whose boxes it passes through. Primitives are referred to by their index
+// in the bounds the BVH was built from.
+type BVH struct {
+	nodes []bvhNode // The root first, and each node's left child straight after it
+	order []int     // Primitives, in the order the leaves refer to them
+}
+
+type bvhNode struct {
+	bounds AABB
+	start  int // Leaves hold order[start : start+count]
+	count  int // 0 for interior nodes
+	right  int // Interior nodes' second child
+	axis   int // Interior nodes are split along this axis, left child lower
+}
+
+const (
+	bvh_bins           = 16
+	bvh_max_leaf       = 4
+	bvh_traversal_cost = 1.0 // Relative to testing a primitive
+)
+
+func BuildBVH(bounds []AABB) *BVH {
+	// Split with the surface area heuristic: the chance of a ray hitting a
+	// box goes with its surface area, so pick the split which minimises the
+	// expected number of primitives tested. Candidate splits are between
+	// bins of primitive centres along each axis.
+	b := &BVH{order: make([]int, len(bounds))}
+	centres := make([][3]float64, len(bounds))
+	for i, box := range bounds {
+		b.order[i] = i
+		centres[i] = [3]float64{(box.Min.X + box.Max.X) / 2.0, (box.Min.Y + box.Max.Y) / 2.0, (box.Min.Z + box.Max.Z) / 2.0}
+	}
+	if len(bounds) > 0 {
+		b.build(bounds, centres, 0, len(bounds))
+	}
+	return b
+}
+
+func (b *BVH) build(bounds []AABB, centres [][3]float64, start int, end int) int {
+	index := len(b.nodes)
+	node := bvhNode{bounds: EmptyAABB(), start: start, count: end - start}
+	centre_min := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	centre_max := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, i := range b.order[start:end] {
+		node.bounds = node.bounds.Union(bounds[i])
+		for axis := range centre_min {
+			centre_min[axis] = math.Min(centre_min[axis], centres[i][axis])
+			centre_max[axis] = math.Max(centre_max[axis], centres[i][axis])
+		}
+	}
+	b.nodes = append(b.nodes, node)
+	if node.count == 1 {
+		return index
+	}
+
+	best_cost, best_axis, best_bin := math.Inf(1), -1, 0
+	parent_area := surfaceArea(node.bounds)
+	for axis := range centre_min {
+		extent := centre_max[axis] - centre_min[axis]
+		if extent <= 0.0 {
+			continue
+		}
+		var counts [bvh_bins]int
+		var boxes [bvh_bins]AABB
+		for bin := range boxes {
+			boxes[bin] = EmptyAABB()
+		}
+		for _, i := range b.order[start:end] {
+			bin := binOf(centres[i][axis], centre_min[axis], extent)
+			counts[bin]++
+			boxes[bin] = boxes[bin].Union(bounds[i])
+		}
+		// Costs of splitting after each bin, sweeping in from both ends
+		var right_areas [bvh_bins]float64
+		var right_counts [bvh_bins]int
+		right_box, right_count := EmptyAABB(), 0
+		for bin := bvh_bins - 1; bin > 0; bin-- {
+			right_box = right_box.Union(boxes[bin])
+			right_count += counts[bin]
+			right_areas[bin], right_counts[bin] = surfaceArea(right_box), right_count
+		}
+		left_box, left_count := EmptyAABB(), 0
+		for bin := 0; bin < bvh_bins-1; bin++ {
+			left_box = left_box.Union(boxes[bin])
+			left_count += counts[bin]
+			if left_count == 0 || right_counts[bin+1] == 0 {
+				continue
+			}
+			cost := bvh_traversal_cost + (surfaceArea(left_box)*float64(left_count)+right_areas[bin+1]*float64(right_counts[bin+1]))/parent_area
+			if cost < best_cost {
+				best_cost, best_axis, best_bin = cost, axis, bin
+			}
+		}
+	}
+
+	if best_axis < 0 || (best_cost >= float64(node.count) && node.count <= bvh_max_leaf) {
+		// All the centres are in the same place, or splitting isn't worth it
+		return index
+	}
+
+	// Partition order so everything in the lower bins comes first
+	axis := best_axis
+	extent := centre_max[axis] - centre_min[axis]
+	mid := start
+	for j := start; j < end; j++ {
+		if binOf(centres[b.order[j]][axis], centre_min[axis], extent) <= best_bin {
+			b.order[j], b.order[mid] = b.order[mid], b.order[j]
+			mid++
+		}
+	}
+	if mid == start || mid == end {
+		// Only happens with rounding; split in half instead
+		primitives := b.order[start:end]
+		sort.Slice(primitives, func(j, k int) bool { return centres[primitives[j]][axis] < centres[primitives[k]][axis] })
+		mid = (start + end) / 2
+	}
+
+	b.nodes[index].count = 0
+	b.nodes[index].axis = axis
+	b.build(bounds, centres, start, mid)
+	right := b.build(bounds, centres, mid, end)
+	b.nodes[index].right = right
+	return index
+}
+
+func binOf(centre float64, min float64, extent float64) int {
+	bin := int(bvh_bins * (centre - min) / extent)
+	if bin >= bvh_bins {
+		return bvh_bins - 1
+	}
+	return bin
+}
+
+func surfaceArea(b AABB) float64 {
+	dx, dy, dz := b.Max.X-b.Min.X, b.Max.Y-b.Min.Y, b.Max.Z-b.Min.Z
+	if dx < 0.0 || dy < 0.0 || dz < 0.0 {
+		return 0.0
+	}
+	return 2.0 * (dx*dy + dy*dz + dz*dx)
+}
+
+func (b *BVH) Traverse(test_ray rays.Ray, t_min float64, t_max float64, visit Visitor) {
+	// Nearer children are visited first, so closest hits shrink t_max early
+	// and more of the tree can be skipped.
+	if len(b.nodes) == 0 {
+		return
+	}
+	direction := [3]float64{test_ray.Direction.X, test_ray.Direction.Y, test_ray.Direction.Z}
+	var stack_space [64]int
+	stack := append(stack_space[:0], 0)
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &b.nodes[index]
+		t_near, t_far, ok := node.bounds.Hit(test_ray)
+		if !ok || t_near >= t_max || t_far <= t_min {
+			continue
+		}
+		if node.count > 0 {
+			for _, primitive := range b.order[node.start : node.start+node.count] {
+				var stop bool
+				if t_max, stop = visit(primitive, t_max); stop {
+					return
+				}
+			}
+			continue
+		}
+		// The left child is straight after its parent, and is the nearer one
+		// for rays going up the split axis. The nearer goes on the stack last.
+		if direction[node.axis] < 0.0 {
+			stack = append(stack, index+1, node.right)
+		} else {
+			stack = append(stack, node.right, index+1)
+		}
+	}
+}
+
+func (b *BVH) Overlapping(box AABB, visit func(primitive int)) {
+	// Every primitive whose box overlaps box, in no particular order.
+	if len(b.nodes) == 0 {
+		return
+	}
+	var stack_space [64]int
+	stack := append(stack_space[:0], 0)
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := &b.nodes[index]
+		if !node.bounds.Overlaps(box) {
+			continue
+		}
+		if node.count > 0 {
+			for _, primitive := range b.order[node.start : node.start+node.count] {
+				visit(primitive)
+			}
+			continue
+		}
+		stack = append(stack, node.right, index+1)
+	}
+}
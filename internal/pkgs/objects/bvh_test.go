@@ -0,0 +1,145 @@
+package objects
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func randomSpheres(n int, r *rand.Rand) []Object {
+	// Scattered over a 100 unit cube, some overlapping
+	spheres := make([]Object, n)
+	for i := range spheres {
+		spheres[i] = Sphere{
+			Radius: 0.5 + 2.0*r.Float64(),
+			Center: vectors.Vector{X: 100.0 * r.Float64(), Y: 100.0 * r.Float64(), Z: 100.0 * r.Float64()},
+		}
+	}
+	return spheres
+}
+
+func randomRay(r *rand.Rand) rays.Ray {
+	// From somewhere around the cube, towards somewhere in it
+	origin := &vectors.Vector{X: 200.0*r.Float64() - 50.0, Y: 200.0*r.Float64() - 50.0, Z: 200.0*r.Float64() - 50.0}
+	target := &vectors.Vector{X: 100.0 * r.Float64(), Y: 100.0 * r.Float64(), Z: 100.0 * r.Float64()}
+	return rays.MakeRay(origin, target.Subtract(origin))
+}
+
+func boundsOf(objs []Object) []AABB {
+	bounds := make([]AABB, len(objs))
+	for i, obj := range objs {
+		bounds[i], _ = BoundsOf(obj)
+	}
+	return bounds
+}
+
+func checkTraverse(t *testing.T, accelerator Accelerator, objs []Object, r *rand.Rand) {
+	// Against testing every object, for closest hits, any hits and all hits.
+	for n := 0; n < 500; n++ {
+		ray := randomRay(r)
+		want_closest := math.Inf(1)
+		var want_all []float64
+		for _, obj := range objs {
+			dists := obj.CollideDistances(ray)
+			want_all = append(want_all, dists...)
+			if len(dists) > 0 {
+				want_closest = math.Min(want_closest, dists[0])
+			}
+		}
+		sort.Float64s(want_all)
+
+		got_closest := math.Inf(1)
+		accelerator.Traverse(ray, 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+			if hit, ok := Intersect(objs[i], ray, 0.0, t_max); ok {
+				got_closest = hit.T
+				return hit.T, false
+			}
+			return t_max, false
+		})
+		if got_closest != want_closest {
+			t.Fatalf("%T closest hit = %v, want %v", accelerator, got_closest, want_closest)
+		}
+
+		// Some accelerators visit objects more than once
+		visited := map[int]bool{}
+		var got_all []float64
+		accelerator.Traverse(ray, 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+			if !visited[i] {
+				visited[i] = true
+				got_all = append(got_all, objs[i].CollideDistances(ray)...)
+			}
+			return t_max, false
+		})
+		sort.Float64s(got_all)
+		if !utils.Slice_close_enough(got_all, want_all) {
+			t.Fatalf("%T all hits = %v, want %v", accelerator, got_all, want_all)
+		}
+
+		// Stopping at the first object hit
+		found := false
+		accelerator.Traverse(ray, 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+			found = len(objs[i].CollideDistances(ray)) > 0
+			return t_max, found
+		})
+		if found != (len(want_all) > 0) {
+			t.Fatalf("%T any hit = %v, want %v", accelerator, found, len(want_all) > 0)
+		}
+	}
+}
+
+func TestBVH_Traverse(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	spheres := randomSpheres(500, r)
+	checkTraverse(t, BuildBVH(boundsOf(spheres)), spheres, r)
+}
+
+func TestBVH_Traverse_empty(t *testing.T) {
+	bvh := BuildBVH(nil)
+	bvh.Traverse(randomRay(rand.New(rand.NewSource(1))), 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+		t.Errorf("Empty BVH visited %v", i)
+		return t_max, false
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	// The floor is unbounded so isn't in the accelerator, but still gets hit
+	floor := Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}}
+	near := Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}}
+	far := Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	tests := []struct {
+		name       string
+		ray        rays.Ray
+		t_max      float64
+		wantT      float64
+		wantOk     bool
+		wantAny    bool
+		wantObject Object
+	}{
+		{name: "Nearest sphere", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), t_max: math.Inf(1), wantT: 4.0, wantOk: true, wantAny: true, wantObject: near},
+		{name: "Floor", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}), t_max: math.Inf(1), wantT: 1.0, wantOk: true, wantAny: true, wantObject: floor},
+		{name: "Floor in front of the spheres", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 3.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -4.0, Z: 2.5}), t_max: math.Inf(1), wantT: 4.716990566028302, wantOk: true, wantAny: true, wantObject: floor},
+		{name: "Short of everything", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), t_max: 3.0, wantOk: false, wantAny: false},
+	}
+	kinds := map[string]AcceleratorKind{"BVH": BVHAccelerator, "Grid": GridAccelerator, "KDTree": KDTreeAccelerator}
+	for kind_name, kind := range kinds {
+		aggregate := MakeAggregateUsing([]Object{far, floor, near}, kind)
+		for _, tt := range tests {
+			t.Run(kind_name+"/"+tt.name, func(t *testing.T) {
+				hit, ok := aggregate.Intersect(tt.ray, 0.0, tt.t_max)
+				if ok != tt.wantOk || (ok && (!utils.Close_enough(hit.T, tt.wantT) || hit.Object != tt.wantObject)) {
+					t.Errorf("Aggregate.Intersect() = %v, %v on %v, want %v, %v on %v", hit.T, ok, hit.Object, tt.wantT, tt.wantOk, tt.wantObject)
+				}
+				// Any blocker will do, so long as it really is in the way
+				blocker, got := aggregate.Occluded(tt.ray, 0.0, tt.t_max)
+				if got != tt.wantAny || (got && !Occluded(blocker, tt.ray, 0.0, tt.t_max)) {
+					t.Errorf("Aggregate.Occluded() = %v, %v, want %v", blocker, got, tt.wantAny)
+				}
+			})
+		}
+	}
+}
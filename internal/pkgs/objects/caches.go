@@ -0,0 +1,463 @@
+package objects
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Bump whenever an accelerator's build or the file layout changes, so older
+// caches are rebuilt rather than loaded.
+const accelerator_cache_version uint32 = 2
+
+// AcceleratorCache keeps built accelerators in files in Dir, named by a
+// hash of their kind and the bounds they were built from, so the same
+// geometry is only built once. Meshes use it through MakeMeshCached, and
+// scenes through Scene.AcceleratedCached.
+type AcceleratorCache struct {
+	Dir string
+}
+
+func AcceleratorKey(kind AcceleratorKind, bounds []AABB) [sha256.Size]byte {
+	// A hash of everything BuildAccelerator depends on.
+	h := sha256.New()
+	var buf [8]byte
+	binary.LittleEndian.PutUint32(buf[:4], accelerator_cache_version)
+	h.Write(buf[:4])
+	binary.LittleEndian.PutUint32(buf[:4], uint32(kind))
+	h.Write(buf[:4])
+	binary.LittleEndian.PutUint64(buf[:], uint64(len(bounds)))
+	h.Write(buf[:])
+	for _, b := range bounds {
+		for _, value := range [6]float64{b.Min.X, b.Min.Y, b.Min.Z, b.Max.X, b.Max.Y, b.Max.Z} {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(value))
+			h.Write(buf[:])
+		}
+	}
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
+
+func cacheMagic(kind AcceleratorKind) ([4]byte, error) {
+	// Says what a file is, and what kind of accelerator is in it.
+	switch kind {
+	case BVHAccelerator:
+		return [4]byte{'G', 'R', 'T', 'B'}, nil
+	case GridAccelerator:
+		return [4]byte{'G', 'R', 'T', 'G'}, nil
+	case KDTreeAccelerator:
+		return [4]byte{'G', 'R', 'T', 'K'}, nil
+	}
+	return [4]byte{}, fmt.Errorf("accelerator cache: unknown accelerator kind %v", kind)
+}
+
+func (c AcceleratorCache) path(key [sha256.Size]byte) string {
+	return filepath.Join(c.Dir, hex.EncodeToString(key[:])+".accel")
+}
+
+func (c AcceleratorCache) Build(kind AcceleratorKind, bounds []AABB) (Accelerator, error) {
+	// The cached accelerator for bounds if there's a good one, otherwise a
+	// new one, which is saved for next time. The accelerator is always
+	// usable; the error only says it couldn't be saved.
+	if a, err := c.Load(kind, bounds); err == nil {
+		return a, nil
+	}
+	a := BuildAccelerator(kind, bounds)
+	return a, c.Save(kind, bounds, a)
+}
+
+func (c AcceleratorCache) BuildBVH(bounds []AABB) (*BVH, error) {
+	a, err := c.Build(BVHAccelerator, bounds)
+	return a.(*BVH), err
+}
+
+func (c AcceleratorCache) Load(kind AcceleratorKind, bounds []AABB) (Accelerator, error) {
+	// Fails if there's no cached accelerator for bounds, or it's corrupt or
+	// stale.
+	key := AcceleratorKey(kind, bounds)
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadAccelerator(bufio.NewReader(f), kind, key, len(bounds))
+}
+
+func (c AcceleratorCache) Save(kind AcceleratorKind, bounds []AABB, a Accelerator) error {
+	// Written to a temporary file first, so other renders never see half a
+	// cache file.
+	key := AcceleratorKey(kind, bounds)
+	err := os.MkdirAll(c.Dir, 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.Dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	w := bufio.NewWriter(f)
+	err = WriteAccelerator(w, a, key)
+	if err == nil {
+		err = w.Flush()
+	}
+	if close_err := f.Close(); err == nil {
+		err = close_err
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path(key))
+}
+
+func WriteAccelerator(w io.Writer, a Accelerator, key [sha256.Size]byte) error {
+	// A header saying what the accelerator is and what it's for, its
+	// contents, then a checksum of all that. Everything is little endian.
+	var kind AcceleratorKind
+	switch a.(type) {
+	case *BVH:
+		kind = BVHAccelerator
+	case *Grid:
+		kind = GridAccelerator
+	case *KDTree:
+		kind = KDTreeAccelerator
+	default:
+		return fmt.Errorf("accelerator cache: can't save a %T", a)
+	}
+	magic, err := cacheMagic(kind)
+	if err != nil {
+		return err
+	}
+	data := append([]byte{}, magic[:]...)
+	data = appendUint32(data, accelerator_cache_version)
+	data = append(data, key[:]...)
+	switch a := a.(type) {
+	case *BVH:
+		data = a.appendTo(data)
+	case *Grid:
+		data = a.appendTo(data)
+	case *KDTree:
+		data = a.appendTo(data)
+	}
+	data = appendUint32(data, crc32.ChecksumIEEE(data))
+	_, err = w.Write(data)
+	return err
+}
+
+func ReadAccelerator(r io.Reader, kind AcceleratorKind, key [sha256.Size]byte, primitives int) (Accelerator, error) {
+	// Checks it's the kind of accelerator wanted for key, over that many
+	// primitives, and that it's all there and makes sense, so a bad file
+	// can't break a render.
+	magic, err := cacheMagic(kind)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("accelerator cache: %v", err)
+	}
+	if len(data) < 4 || crc32.ChecksumIEEE(data[:len(data)-4]) != binary.LittleEndian.Uint32(data[len(data)-4:]) {
+		return nil, fmt.Errorf("accelerator cache: checksum mismatch")
+	}
+	in := &cacheDecoder{data: data[:len(data)-4]}
+	if got := in.bytes(4); string(got) != string(magic[:]) {
+		return nil, fmt.Errorf("accelerator cache: not a cache file for this kind of accelerator")
+	}
+	if version := in.uint32(); version != accelerator_cache_version {
+		return nil, fmt.Errorf("accelerator cache: version %d, want %d", version, accelerator_cache_version)
+	}
+	if got := in.bytes(sha256.Size); string(got) != string(key[:]) {
+		return nil, fmt.Errorf("accelerator cache: built from different geometry")
+	}
+
+	var a interface {
+		Accelerator
+		validate(primitives int) error
+	}
+	switch kind {
+	case BVHAccelerator:
+		a = readBVH(in)
+	case GridAccelerator:
+		a = readGrid(in)
+	case KDTreeAccelerator:
+		a = readKDTree(in)
+	}
+	if in.err == nil && len(in.data) > 0 {
+		in.err = fmt.Errorf("%d bytes left over", len(in.data))
+	}
+	if in.err != nil {
+		return nil, fmt.Errorf("accelerator cache: %v", in.err)
+	}
+	if err := a.validate(primitives); err != nil {
+		return nil, fmt.Errorf("accelerator cache: %v", err)
+	}
+	return a, nil
+}
+
+// Reads values written by the appendTo methods, and remembers the first
+// thing that went wrong, after which everything reads as zero
+type cacheDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *cacheDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return make([]byte, n)
+	}
+	if n > len(d.data) {
+		d.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *cacheDecoder) uint32() uint32 {
+	return binary.LittleEndian.Uint32(d.bytes(4))
+}
+
+func (d *cacheDecoder) float64() float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(d.bytes(8)))
+}
+
+func (d *cacheDecoder) aabb() AABB {
+	var values [6]float64
+	for i := range values {
+		values[i] = d.float64()
+	}
+	return AABB{
+		Min: vectors.Vector{X: values[0], Y: values[1], Z: values[2]},
+		Max: vectors.Vector{X: values[3], Y: values[4], Z: values[5]},
+	}
+}
+
+func (d *cacheDecoder) count(record_size int) int {
+	// How many records of record_size follow, as long as they're all there,
+	// so a bad count can't ask for a huge slice.
+	n := d.uint32()
+	if d.err == nil && uint64(n)*uint64(record_size) > uint64(len(d.data)) {
+		d.err = io.ErrUnexpectedEOF
+	}
+	if d.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (d *cacheDecoder) ints(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = int(d.uint32())
+	}
+	return values
+}
+
+func appendAABB(buf []byte, b AABB) []byte {
+	for _, value := range [6]float64{b.Min.X, b.Min.Y, b.Min.Z, b.Max.X, b.Max.Y, b.Max.Z} {
+		buf = appendUint64(buf, math.Float64bits(value))
+	}
+	return buf
+}
+
+func appendInts(buf []byte, values []int) []byte {
+	buf = appendUint32(buf, uint32(len(values)))
+	for _, value := range values {
+		buf = appendUint32(buf, uint32(value))
+	}
+	return buf
+}
+
+// Each node's bounds, then start, count, right and axis
+const bvh_node_size = 6*8 + 4*4
+
+func (b *BVH) appendTo(buf []byte) []byte {
+	// Its nodes, then its order.
+	buf = appendUint32(buf, uint32(len(b.nodes)))
+	for _, node := range b.nodes {
+		buf = appendAABB(buf, node.bounds)
+		for _, value := range [4]int{node.start, node.count, node.right, node.axis} {
+			buf = appendUint32(buf, uint32(value))
+		}
+	}
+	return appendInts(buf, b.order)
+}
+
+func readBVH(in *cacheDecoder) *BVH {
+	b := &BVH{nodes: make([]bvhNode, in.count(bvh_node_size))}
+	for i := range b.nodes {
+		b.nodes[i] = bvhNode{bounds: in.aabb()}
+		b.nodes[i].start = int(in.uint32())
+		b.nodes[i].count = int(in.uint32())
+		b.nodes[i].right = int(in.uint32())
+		b.nodes[i].axis = int(in.uint32())
+	}
+	b.order = in.ints(in.count(4))
+	return b
+}
+
+func (b *BVH) validate(primitives int) error {
+	// Every index in range, and every primitive in exactly one leaf.
+	if len(b.order) != primitives {
+		return fmt.Errorf("%d primitives, want %d", len(b.order), primitives)
+	}
+	seen := make([]bool, len(b.order))
+	for _, primitive := range b.order {
+		if primitive < 0 || primitive >= len(seen) || seen[primitive] {
+			return fmt.Errorf("bad primitive %d", primitive)
+		}
+		seen[primitive] = true
+	}
+	if len(b.order) > 0 && len(b.nodes) == 0 {
+		return fmt.Errorf("no nodes")
+	}
+	leaves := 0
+	for i, node := range b.nodes {
+		if node.count > 0 {
+			if node.start < 0 || node.start+node.count > len(b.order) {
+				return fmt.Errorf("node %d: bad leaf", i)
+			}
+			leaves += node.count
+		} else if i+1 >= len(b.nodes) || node.right <= i+1 || node.right >= len(b.nodes) || node.axis < 0 || node.axis > 2 {
+			return fmt.Errorf("node %d: bad children", i)
+		}
+	}
+	if leaves != len(b.order) {
+		return fmt.Errorf("leaves hold %d primitives, want %d", leaves, len(b.order))
+	}
+	return nil
+}
+
+func (g *Grid) appendTo(buf []byte) []byte {
+	// Its bounds, resolution and cell size, then its cells and items.
+	buf = appendAABB(buf, g.bounds)
+	for axis := range g.resolution {
+		buf = appendUint32(buf, uint32(g.resolution[axis]))
+		buf = appendUint64(buf, math.Float64bits(g.cell_size[axis]))
+	}
+	buf = appendInts(buf, g.cells)
+	return appendInts(buf, g.items)
+}
+
+func readGrid(in *cacheDecoder) *Grid {
+	g := &Grid{bounds: in.aabb()}
+	for axis := range g.resolution {
+		g.resolution[axis] = int(in.uint32())
+		g.cell_size[axis] = in.float64()
+	}
+	g.cells = in.ints(in.count(4))
+	g.items = in.ints(in.count(4))
+	return g
+}
+
+func (g *Grid) validate(primitives int) error {
+	// Every cell's items in range, and every item a primitive.
+	if len(g.items) == 0 {
+		if primitives > 0 {
+			return fmt.Errorf("no items, want %d primitives", primitives)
+		}
+		return nil
+	}
+	n_cells := 1
+	for axis, resolution := range g.resolution {
+		if resolution < 1 || resolution > grid_max_resolution || !(g.cell_size[axis] >= 0.0) {
+			return fmt.Errorf("bad resolution %d along axis %d", resolution, axis)
+		}
+		n_cells *= resolution
+	}
+	if len(g.cells) != n_cells+1 || g.cells[0] != 0 || g.cells[n_cells] != len(g.items) {
+		return fmt.Errorf("%d cells holding %d items, want %d", len(g.cells)-1, len(g.items), n_cells)
+	}
+	for i := 1; i < len(g.cells); i++ {
+		if g.cells[i] < g.cells[i-1] {
+			return fmt.Errorf("cell %d: bad items", i-1)
+		}
+	}
+	for _, primitive := range g.items {
+		if primitive < 0 || primitive >= primitives {
+			return fmt.Errorf("bad primitive %d", primitive)
+		}
+	}
+	return nil
+}
+
+// Each node's split, then axis, upper, start, count and whether it's a leaf
+const kd_node_size = 8 + 5*4
+
+func (k *KDTree) appendTo(buf []byte) []byte {
+	// Its bounds, then its nodes and items.
+	buf = appendAABB(buf, k.bounds)
+	buf = appendUint32(buf, uint32(len(k.nodes)))
+	for _, node := range k.nodes {
+		buf = appendUint64(buf, math.Float64bits(node.split))
+		leaf := 0
+		if node.leaf {
+			leaf = 1
+		}
+		for _, value := range [5]int{node.axis, node.upper, node.start, node.count, leaf} {
+			buf = appendUint32(buf, uint32(value))
+		}
+	}
+	return appendInts(buf, k.items)
+}
+
+func readKDTree(in *cacheDecoder) *KDTree {
+	k := &KDTree{bounds: in.aabb()}
+	k.nodes = make([]kdNode, in.count(kd_node_size))
+	for i := range k.nodes {
+		k.nodes[i].split = in.float64()
+		k.nodes[i].axis = int(in.uint32())
+		k.nodes[i].upper = int(in.uint32())
+		k.nodes[i].start = int(in.uint32())
+		k.nodes[i].count = int(in.uint32())
+		k.nodes[i].leaf = in.uint32() != 0
+	}
+	k.items = in.ints(in.count(4))
+	return k
+}
+
+func (k *KDTree) validate(primitives int) error {
+	// Every index in range, and children always after their parents, so a
+	// walk down the tree ends.
+	if primitives > 0 && len(k.nodes) == 0 {
+		return fmt.Errorf("no nodes, want %d primitives", primitives)
+	}
+	for _, primitive := range k.items {
+		if primitive < 0 || primitive >= primitives {
+			return fmt.Errorf("bad primitive %d", primitive)
+		}
+	}
+	for i, node := range k.nodes {
+		if node.leaf {
+			if node.start < 0 || node.count < 0 || node.start+node.count > len(k.items) {
+				return fmt.Errorf("node %d: bad leaf", i)
+			}
+		} else if i+1 >= len(k.nodes) || node.upper <= i+1 || node.upper >= len(k.nodes) || node.axis < 0 || node.axis > 2 {
+			return fmt.Errorf("node %d: bad children", i)
+		}
+	}
+	return nil
+}
+
+func appendUint32(buf []byte, value uint32) []byte {
+	var encoded [4]byte
+	binary.LittleEndian.PutUint32(encoded[:], value)
+	return append(buf, encoded[:]...)
+}
+
+func appendUint64(buf []byte, value uint64) []byte {
+	var encoded [8]byte
+	binary.LittleEndian.PutUint64(encoded[:], value)
+	return append(buf, encoded[:]...)
+}
@@ -0,0 +1,258 @@
+package objects
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+var accelerator_kinds = map[string]AcceleratorKind{
+	"BVH":     BVHAccelerator,
+	"Grid":    GridAccelerator,
+	"KD tree": KDTreeAccelerator,
+}
+
+func TestAcceleratorCache_Build(t *testing.T) {
+	for name, kind := range accelerator_kinds {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			spheres := randomSpheres(500, r)
+			bounds := boundsOf(spheres)
+			cache := AcceleratorCache{Dir: filepath.Join(t.TempDir(), "cache")}
+			if _, err := cache.Load(kind, bounds); err == nil {
+				t.Fatalf("AcceleratorCache.Load() found an accelerator in an empty cache")
+			}
+			built, err := cache.Build(kind, bounds)
+			if err != nil {
+				t.Fatalf("AcceleratorCache.Build() error = %v", err)
+			}
+			loaded, err := cache.Load(kind, bounds)
+			if err != nil {
+				t.Fatalf("AcceleratorCache.Load() error = %v after building", err)
+			}
+			if !reflect.DeepEqual(loaded, built) || !reflect.DeepEqual(loaded, BuildAccelerator(kind, bounds)) {
+				t.Errorf("AcceleratorCache.Load() isn't the accelerator that was built")
+			}
+			checkTraverse(t, loaded, spheres, r)
+
+			// Other geometry, and other kinds, have their own entries
+			moved := append([]AABB{}, bounds...)
+			moved[0] = moved[0].Expand(&vectors.Vector{X: 1.0, Y: 1.0, Z: 1.0})
+			if _, err := cache.Load(kind, moved); err == nil {
+				t.Errorf("AcceleratorCache.Load() found an accelerator for geometry it hasn't seen")
+			}
+			if _, err := cache.Load(kind, bounds[1:]); err == nil {
+				t.Errorf("AcceleratorCache.Load() found an accelerator for fewer primitives")
+			}
+			for _, other := range accelerator_kinds {
+				if _, err := cache.Load(other, bounds); other != kind && err == nil {
+					t.Errorf("AcceleratorCache.Load() found a %v for a %v", other, kind)
+				}
+			}
+			entries, _ := os.ReadDir(cache.Dir)
+			if len(entries) != 1 {
+				t.Errorf("AcceleratorCache left %v files, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestAcceleratorCache_Build_empty(t *testing.T) {
+	// Scenes with nothing bounded still have an accelerator, over nothing
+	for name, kind := range accelerator_kinds {
+		t.Run(name, func(t *testing.T) {
+			cache := AcceleratorCache{Dir: t.TempDir()}
+			if _, err := cache.Build(kind, nil); err != nil {
+				t.Fatalf("AcceleratorCache.Build() error = %v", err)
+			}
+			if _, err := cache.Load(kind, nil); err != nil {
+				t.Errorf("AcceleratorCache.Load() error = %v for no primitives", err)
+			}
+		})
+	}
+}
+
+func TestAcceleratorCache_Load_bad(t *testing.T) {
+	// Corrupt, cut short or from an older version: all rebuilt, and fine
+	// after that
+	bounds := boundsOf(randomSpheres(100, rand.New(rand.NewSource(1))))
+	tests := []struct {
+		name  string
+		spoil func(data []byte) []byte
+	}{
+		{name: "Flipped bit", spoil: func(data []byte) []byte { data[len(data)/2] ^= 0x10; return data }},
+		{name: "Flipped checksum", spoil: func(data []byte) []byte { data[len(data)-1] ^= 0x01; return data }},
+		{name: "Truncated", spoil: func(data []byte) []byte { return data[:len(data)-100] }},
+		{name: "Empty", spoil: func(data []byte) []byte { return nil }},
+		{name: "Older version", spoil: func(data []byte) []byte { data[4]--; return data }},
+		{name: "Not a cache", spoil: func(data []byte) []byte { copy(data, "PNG!"); return data }},
+	}
+	for kind_name, kind := range accelerator_kinds {
+		want := BuildAccelerator(kind, bounds)
+		for _, tt := range tests {
+			t.Run(kind_name+"/"+tt.name, func(t *testing.T) {
+				cache := AcceleratorCache{Dir: t.TempDir()}
+				if err := cache.Save(kind, bounds, want); err != nil {
+					t.Fatalf("AcceleratorCache.Save() error = %v", err)
+				}
+				path := cache.path(AcceleratorKey(kind, bounds))
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, tt.spoil(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := cache.Load(kind, bounds); err == nil {
+					t.Fatalf("AcceleratorCache.Load() loaded a spoilt cache")
+				}
+				got, err := cache.Build(kind, bounds)
+				if err != nil || !reflect.DeepEqual(got, want) {
+					t.Fatalf("AcceleratorCache.Build() = %v, didn't rebuild", err)
+				}
+				if _, err := cache.Load(kind, bounds); err != nil {
+					t.Errorf("AcceleratorCache.Load() error = %v after rebuilding", err)
+				}
+			})
+		}
+	}
+}
+
+func TestBVH_validate(t *testing.T) {
+	// Well-formed files which still don't describe a BVH
+	good := BuildBVH(boundsOf(randomSpheres(50, rand.New(rand.NewSource(1)))))
+	if err := good.validate(50); err != nil {
+		t.Fatalf("BVH.validate() = %v for a built BVH", err)
+	}
+	tests := []struct {
+		name  string
+		spoil func(b *BVH)
+	}{
+		{name: "Repeated primitive", spoil: func(b *BVH) { b.order[1] = b.order[0] }},
+		{name: "Primitive out of range", spoil: func(b *BVH) { b.order[0] = len(b.order) }},
+		{name: "Child out of range", spoil: func(b *BVH) { b.nodes[0].right = len(b.nodes) }},
+		{name: "Child before its parent", spoil: func(b *BVH) { b.nodes[0].right = 0 }},
+		{name: "Bad axis", spoil: func(b *BVH) { b.nodes[0].axis = 3 }},
+		{name: "Leaf past the end", spoil: func(b *BVH) { b.nodes[len(b.nodes)-1].count = len(b.order) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &BVH{nodes: append([]bvhNode{}, good.nodes...), order: append([]int{}, good.order...)}
+			tt.spoil(b)
+			if err := b.validate(50); err == nil {
+				t.Errorf("BVH.validate() passed a bad BVH")
+			}
+		})
+	}
+}
+
+func TestGrid_validate(t *testing.T) {
+	// Well-formed files which still don't describe a grid
+	good := BuildGrid(boundsOf(randomSpheres(50, rand.New(rand.NewSource(1)))))
+	if err := good.validate(50); err != nil {
+		t.Fatalf("Grid.validate() = %v for a built grid", err)
+	}
+	tests := []struct {
+		name  string
+		spoil func(g *Grid)
+	}{
+		{name: "Primitive out of range", spoil: func(g *Grid) { g.items[0] = 50 }},
+		{name: "Too many cells", spoil: func(g *Grid) { g.resolution[0]++ }},
+		{name: "No cells", spoil: func(g *Grid) { g.resolution[1] = 0 }},
+		{name: "Cell ends before it starts", spoil: func(g *Grid) { g.cells[1] = len(g.items) + 1 }},
+		{name: "Cells past the end", spoil: func(g *Grid) { g.cells[len(g.cells)-1]++ }},
+		{name: "Missing items", spoil: func(g *Grid) { g.items = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := *good
+			g.cells = append([]int{}, good.cells...)
+			g.items = append([]int{}, good.items...)
+			tt.spoil(&g)
+			if err := g.validate(50); err == nil {
+				t.Errorf("Grid.validate() passed a bad grid")
+			}
+		})
+	}
+}
+
+func TestKDTree_validate(t *testing.T) {
+	// Well-formed files which still don't describe a k-d tree
+	good := BuildKDTree(boundsOf(randomSpheres(50, rand.New(rand.NewSource(1)))))
+	if err := good.validate(50); err != nil {
+		t.Fatalf("KDTree.validate() = %v for a built k-d tree", err)
+	}
+	tests := []struct {
+		name  string
+		spoil func(k *KDTree)
+	}{
+		{name: "Primitive out of range", spoil: func(k *KDTree) { k.items[0] = 50 }},
+		{name: "Child out of range", spoil: func(k *KDTree) { k.nodes[0].upper = len(k.nodes) }},
+		{name: "Child before its parent", spoil: func(k *KDTree) { k.nodes[0].upper = 0 }},
+		{name: "Bad axis", spoil: func(k *KDTree) { k.nodes[0].axis = 3 }},
+		{name: "Leaf past the end", spoil: func(k *KDTree) { k.nodes[len(k.nodes)-1].count = len(k.items) + 1 }},
+		{name: "No nodes", spoil: func(k *KDTree) { k.nodes = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := *good
+			k.nodes = append([]kdNode{}, good.nodes...)
+			k.items = append([]int{}, good.items...)
+			tt.spoil(&k)
+			if err := k.validate(50); err == nil {
+				t.Errorf("KDTree.validate() passed a bad k-d tree")
+			}
+		})
+	}
+}
+
+func TestMakeMeshCached(t *testing.T) {
+	// Meshes with the same faces share a cache entry, and get the same BVH
+	cache := AcceleratorCache{Dir: t.TempDir()}
+	tetrahedron := makeTestTetrahedron(nil)
+	make_mesh := func() *Mesh {
+		m, err := MakeMeshCached(cache, tetrahedron.Positions, nil, nil, nil, tetrahedron.Faces, tetrahedron.Material)
+		if err != nil {
+			t.Fatalf("MakeMeshCached() error = %v", err)
+		}
+		return m
+	}
+	first := make_mesh()
+	second := make_mesh()
+	entries, err := os.ReadDir(cache.Dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("MakeMeshCached() cached %v BVHs, %v, want 1", len(entries), err)
+	}
+	if !reflect.DeepEqual(first.bvh, second.bvh) || !reflect.DeepEqual(first.bvh, tetrahedron.bvh) {
+		t.Errorf("MakeMeshCached() loaded a different BVH from the one it built")
+	}
+}
+
+func TestMakeAggregateCached(t *testing.T) {
+	// The same objects get the same accelerator back, and find the same hits
+	r := rand.New(rand.NewSource(1))
+	objs := append(randomSpheres(200, r), Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}})
+	cache := AcceleratorCache{Dir: t.TempDir()}
+	for name, kind := range accelerator_kinds {
+		t.Run(name, func(t *testing.T) {
+			want := MakeAggregateUsing(objs, kind)
+			for i := 0; i < 2; i++ {
+				got, err := MakeAggregateCached(objs, kind, cache)
+				if err != nil {
+					t.Fatalf("MakeAggregateCached() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("MakeAggregateCached() = a different aggregate from MakeAggregateUsing() on try %d", i+1)
+				}
+			}
+		})
+	}
+	entries, _ := os.ReadDir(cache.Dir)
+	if len(entries) != len(accelerator_kinds) {
+		t.Errorf("MakeAggregateCached() cached %v accelerators, want %v", len(entries), len(accelerator_kinds))
+	}
+}
@@ -0,0 +1,124 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A cone on the disc at Base, narrowing to a point Height along the unit
+// vector Axis. Capped closes off the base.
+type Cone struct {
+	Base     vectors.Vector
+	Axis     vectors.Vector
+	Radius   float64
+	Height   float64
+	Capped   bool
+	Material materials.Material
+}
+
+func (c Cone) CollideDistances(test_ray rays.Ray) []float64 {
+	// Points on the side are where the radius across the axis is
+	// Radius * (1 - h / Height) at height h above the base.
+	origin := test_ray.Origin.Subtract(&c.Base)
+	origin_along := origin.Dot(&c.Axis)
+	direction_along := test_ray.Direction.Dot(&c.Axis)
+	origin_across := origin.Subtract(c.Axis.MultiplyScalar(origin_along))
+	direction_across := test_ray.Direction.Subtract(c.Axis.MultiplyScalar(direction_along))
+
+	// The radius along the ray is k (Height - h), a line in the distance
+	slope := c.Radius / c.Height
+	r0 := slope * (c.Height - origin_along)
+	r1 := -slope * direction_along
+
+	var distances []float64
+	side := solveQuadratic(
+		direction_across.Dot(direction_across)-r1*r1,
+		2*(direction_across.Dot(origin_across)-r0*r1),
+		origin_across.Dot(origin_across)-r0*r0,
+	)
+	for _, d := range side {
+		// The equation also has the mirror image cone above the apex
+		if h := origin_along + d*direction_along; h >= 0.0 && h <= c.Height {
+			distances = append(distances, d)
+		}
+	}
+	if c.Capped && direction_along != 0.0 {
+		d := -origin_along / direction_along
+		hit := origin_across.Add(direction_across.MultiplyScalar(d))
+		if hit.Dot(hit) <= c.Radius*c.Radius {
+			distances = append(distances, d)
+		}
+	}
+	return positiveAscending(distances)
+}
+
+func (c Cone) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// As CollideDistances, keeping only the nearest hit in range and the
+	// normal of the part it's on.
+	origin := test_ray.Origin.Subtract(&c.Base)
+	origin_along := origin.Dot(&c.Axis)
+	direction_along := test_ray.Direction.Dot(&c.Axis)
+	origin_across := origin.Subtract(c.Axis.MultiplyScalar(origin_along))
+	direction_across := test_ray.Direction.Subtract(c.Axis.MultiplyScalar(direction_along))
+	slope := c.Radius / c.Height
+	r0 := slope * (c.Height - origin_along)
+	r1 := -slope * direction_along
+
+	found := false
+	var normal vectors.Vector
+	side, n := quadraticRoots(
+		direction_across.Dot(direction_across)-r1*r1,
+		2*(direction_across.Dot(origin_across)-r0*r1),
+		origin_across.Dot(origin_across)-r0*r0,
+	)
+	for _, d := range side[:n] {
+		if h := origin_along + d*direction_along; d > t_min && d < t_max && h >= 0.0 && h <= c.Height {
+			t_max, found = d, true
+			// Out from the axis, tilted up it by the slope
+			normal = *origin_across.Add(direction_across.MultiplyScalar(d))
+			normal.Normalise()
+			normal = *normal.MultiplyScalar(c.Height).Add(c.Axis.MultiplyScalar(c.Radius))
+			normal.Normalise()
+		}
+	}
+	if c.Capped && direction_along != 0.0 {
+		d := -origin_along / direction_along
+		across := origin_across.Add(direction_across.MultiplyScalar(d))
+		if d > t_min && d < t_max && across.Dot(across) <= c.Radius*c.Radius {
+			t_max, found = d, true
+			normal = *c.Axis.MultiplyScalar(-1.0)
+		}
+	}
+	if !found {
+		return Hit{}, false
+	}
+	return makeHit(test_ray, t_max, normal, c.Material), true
+}
+
+func (c Cone) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// Out of the side, tilted up the axis by the slope, or out of the base
+	// if the point is closer to that.
+	offset := surface_point.Subtract(&c.Base)
+	along := offset.Dot(&c.Axis)
+	across := offset.Subtract(c.Axis.MultiplyScalar(along))
+	slant := math.Hypot(c.Radius, c.Height)
+	side_dist := math.Abs(across.Magnitude()-c.Radius*(1.0-along/c.Height)) * c.Height / slant
+	if c.Capped && math.Abs(along) < side_dist {
+		return c.Axis.MultiplyScalar(-1.0)
+	}
+	across.Normalise()
+	normal := across.MultiplyScalar(c.Height).Add(c.Axis.MultiplyScalar(c.Radius))
+	normal.Normalise()
+	return normal
+}
+
+func (c Cone) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, c.Normal(point_of_intersection))
+}
+
+func (c Cone) GetMaterial() materials.Material {
+	return c.Material
+}
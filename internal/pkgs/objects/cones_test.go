@@ -0,0 +1,88 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestCone_CollideDistances(t *testing.T) {
+	// Upright, radius 1 at the base and 2 tall, standing on the origin
+	open := Cone{Base: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Radius: 1.0, Height: 2.0}
+	capped := open
+	capped.Capped = true
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		c    Cone
+		args args
+		want []float64
+	}{
+		{
+			name: "Through the side, half way up",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 1.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{4.5, 5.5},
+		},
+		{
+			name: "Down through the side and base",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.5, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{4.0, 5.0},
+		},
+		{
+			name: "Down through an open cone",
+			c:    open,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.5, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{4.0},
+		},
+		{
+			name: "Up through the open base",
+			c:    open,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.5, Y: -5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0})},
+			want: []float64{6.0},
+		},
+		{
+			name: "Above the apex misses the mirror image cone",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 3.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Beside the base",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 1.5, Y: 0.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Cone.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCone_Normal(t *testing.T) {
+	c := Cone{Base: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Radius: 1.0, Height: 2.0, Capped: true}
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Side", point: &vectors.Vector{X: 0.0, Y: 1.0, Z: -0.5}, want: &vectors.Vector{X: 0.0, Y: 0.4472136, Z: -0.89442719}},
+		{name: "Base", point: &vectors.Vector{X: 0.5, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Cone.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+type CSGOperation int
+
+const (
+	Union        CSGOperation = iota // Inside either
+	Intersection                     // Inside both
+	Difference                       // Inside Left but not Right
+)
+
+// CSG combines two solids, which can be CSGs themselves. Planes count as
+// the half-space behind them. Surfaces which don't enclose anything, like
+// discs, don't make sense here.
+type CSG struct {
+	Operation CSGOperation
+	Left      Object
+	Right     Object
+}
+
+func (c CSG) CollideDistances(test_ray rays.Ray) []float64 {
+	// Walk along the ray through both children's entries and exits together,
+	// keeping the ones where being inside the combination changes.
+	left_dists := c.Left.CollideDistances(test_ray)
+	right_dists := c.Right.CollideDistances(test_ray)
+	in_left := startsInside(c.Left, test_ray, left_dists)
+	in_right := startsInside(c.Right, test_ray, right_dists)
+	inside := c.combine(in_left, in_right)
+
+	var distances []float64
+	i, j := 0, 0
+	for i < len(left_dists) || j < len(right_dists) {
+		var d float64
+		if j == len(right_dists) || (i < len(left_dists) && left_dists[i] <= right_dists[j]) {
+			d = left_dists[i]
+			in_left = !in_left
+			i++
+		} else {
+			d = right_dists[j]
+			in_right = !in_right
+			j++
+		}
+		if now_inside := c.combine(in_left, in_right); now_inside != inside {
+			distances = append(distances, d)
+			inside = now_inside
+		}
+	}
+	return dedupeDistances(distances)
+}
+
+func (c CSG) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// The normal of whichever child the point is on, turned around where
+	// Right has been cut out of Left.
+	if c.onLeft(surface_point) {
+		return c.Left.Normal(surface_point)
+	}
+	normal := c.Right.Normal(surface_point)
+	if c.Operation == Difference {
+		return normal.MultiplyScalar(-1.0)
+	}
+	return normal
+}
+
+func (c CSG) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, c.Normal(point_of_intersection))
+}
+
+func (c CSG) GetMaterial() materials.Material {
+	// Without a point to go on, Left's. MaterialAt gives the right one for each surface.
+	return c.Left.GetMaterial()
+}
+
+func (c CSG) MaterialAt(surface_point *vectors.Vector) materials.Material {
+	child := c.Right
+	if c.onLeft(surface_point) {
+		child = c.Left
+	}
+	if coloured, ok := child.(Coloured); ok {
+		return coloured.MaterialAt(surface_point)
+	}
+	return child.GetMaterial()
+}
+
+func (c CSG) Contains(point *vectors.Vector) bool {
+	return c.combine(contains(c.Left, point), contains(c.Right, point))
+}
+
+func (c CSG) combine(in_left bool, in_right bool) bool {
+	switch c.Operation {
+	case Intersection:
+		return in_left && in_right
+	case Difference:
+		return in_left && !in_right
+	default:
+		return in_left || in_right
+	}
+}
+
+func (c CSG) onLeft(surface_point *vectors.Vector) bool {
+	return surfaceDistance(c.Left, surface_point) <= surfaceDistance(c.Right, surface_point)
+}
+
+// How far rays start back from a point when looking for the surface it's on
+const csg_probe_dist float64 = 1e-4
+
+func surfaceDistance(obj Object, point *vectors.Vector) float64 {
+	// Roughly how far the point is from the object's surface, found by
+	// firing a ray through the point along the object's normal there.
+	normal := obj.Normal(point)
+	if normal.Magnitude() == 0.0 {
+		return math.Inf(1)
+	}
+	normal.Normalise()
+	probe := rays.Ray{Origin: point.Subtract(normal.MultiplyScalar(csg_probe_dist)), Direction: normal}
+	closest := math.Inf(1)
+	for _, d := range obj.CollideDistances(probe) {
+		closest = math.Min(closest, math.Abs(d-csg_probe_dist))
+	}
+	return closest
+}
+
+func startsInside(obj Object, test_ray rays.Ray, distances []float64) bool {
+	// If the ray hits the object, it started inside when its first hit is
+	// on the way out. Otherwise it can only be inside a solid that goes on
+	// forever, like a plane.
+	if len(distances) == 0 {
+		solid, ok := obj.(Solid)
+		return ok && solid.Contains(test_ray.Origin)
+	}
+	first := test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(distances[0]))
+	return test_ray.Direction.Dot(obj.Normal(first)) > 0.0
+}
+
+func contains(obj Object, point *vectors.Vector) bool {
+	if solid, ok := obj.(Solid); ok {
+		return solid.Contains(point)
+	}
+	// An arbitrary direction, unlikely to run along an edge
+	direction := &vectors.Vector{X: 0.5773, Y: 0.5774, Z: 0.5775}
+	probe := rays.MakeRay(point, direction)
+	return startsInside(obj, probe, obj.CollideDistances(probe))
+}
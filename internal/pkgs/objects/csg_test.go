@@ -0,0 +1,191 @@
+package objects
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+var (
+	csg_red  = materials.Material{Color: color.RGBA{0xff, 0, 0, 0xff}}
+	csg_blue = materials.Material{Color: color.RGBA{0, 0, 0xff, 0xff}}
+	// Two overlapping spheres along x, the left one red and the right one blue
+	csg_left  = Sphere{Radius: 2.0, Center: vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0}, Material: csg_red}
+	csg_right = Sphere{Radius: 2.0, Center: vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, Material: csg_blue}
+	// Everything below y = 0
+	csg_floor = Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Material: csg_blue}
+)
+
+func TestCSG_CollideDistances(t *testing.T) {
+	along_x := rays.MakeRay(&vectors.Vector{X: -10.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		c    CSG
+		args args
+		want []float64
+	}{
+		{
+			name: "Union",
+			c:    CSG{Operation: Union, Left: csg_left, Right: csg_right},
+			args: args{test_ray: along_x},
+			want: []float64{7.0, 13.0},
+		},
+		{
+			name: "Intersection",
+			c:    CSG{Operation: Intersection, Left: csg_left, Right: csg_right},
+			args: args{test_ray: along_x},
+			want: []float64{9.0, 11.0},
+		},
+		{
+			name: "Difference",
+			c:    CSG{Operation: Difference, Left: csg_left, Right: csg_right},
+			args: args{test_ray: along_x},
+			want: []float64{7.0, 9.0},
+		},
+		{
+			name: "Difference, from inside what's left",
+			c:    CSG{Operation: Difference, Left: csg_left, Right: csg_right},
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -2.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{1.0},
+		},
+		{
+			name: "Bottom half of a sphere, from above",
+			c:    CSG{Operation: Intersection, Left: csg_left, Right: csg_floor},
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -1.0, Y: 10.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{10.0, 12.0},
+		},
+		{
+			name: "Bottom half of a sphere, level with the cut",
+			c:    CSG{Operation: Intersection, Left: csg_left, Right: csg_floor},
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -10.0, Y: 1.0, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{},
+		},
+		{
+			name: "Bottom half of a sphere, from the side",
+			c:    CSG{Operation: Intersection, Left: csg_left, Right: csg_floor},
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -10.0, Y: -1.0, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{7.26794919, 10.73205081},
+		},
+		{
+			name: "Nested, a lens with a bite taken out",
+			c: CSG{
+				Operation: Difference,
+				Left:      CSG{Operation: Intersection, Left: csg_left, Right: csg_right},
+				Right:     Sphere{Radius: 0.5, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}},
+			},
+			args: args{test_ray: along_x},
+			want: []float64{9.0, 9.5, 10.5, 11.0},
+		},
+		{
+			name: "Nested planes, a slab",
+			c: CSG{
+				Operation: Intersection,
+				Left:      csg_floor,
+				Right:     Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: -2.0, Z: 0.0}},
+			},
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{5.0, 7.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("CSG.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSG_Normal(t *testing.T) {
+	tests := []struct {
+		name         string
+		c            CSG
+		point        *vectors.Vector
+		want         *vectors.Vector
+		wantMaterial materials.Material
+	}{
+		{
+			name:         "Union, on the left",
+			c:            CSG{Operation: Union, Left: csg_left, Right: csg_right},
+			point:        &vectors.Vector{X: -3.0, Y: 0.0, Z: 0.0},
+			want:         &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0},
+			wantMaterial: csg_red,
+		},
+		{
+			name:         "Intersection, on the right sphere's surface",
+			c:            CSG{Operation: Intersection, Left: csg_left, Right: csg_right},
+			point:        &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0},
+			want:         &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0},
+			wantMaterial: csg_blue,
+		},
+		{
+			name:         "Difference, in the hollow",
+			c:            CSG{Operation: Difference, Left: csg_left, Right: csg_right},
+			point:        &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0},
+			want:         &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0},
+			wantMaterial: csg_blue,
+		},
+		{
+			name:         "Cut face of a hemisphere",
+			c:            CSG{Operation: Intersection, Left: csg_left, Right: csg_floor},
+			point:        &vectors.Vector{X: -1.5, Y: 0.0, Z: 0.5},
+			want:         &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+			wantMaterial: csg_blue,
+		},
+		{
+			name:         "Round side of a hemisphere",
+			c:            CSG{Operation: Intersection, Left: csg_left, Right: csg_floor},
+			point:        &vectors.Vector{X: -1.0, Y: -2.0, Z: 0.0},
+			want:         &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0},
+			wantMaterial: csg_red,
+		},
+		{
+			name: "Nested",
+			c: CSG{
+				Operation: Difference,
+				Left:      CSG{Operation: Intersection, Left: csg_left, Right: csg_right},
+				Right:     Sphere{Radius: 0.5, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Material: csg_red},
+			},
+			point:        &vectors.Vector{X: 0.0, Y: 0.5, Z: 0.0},
+			want:         &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0},
+			wantMaterial: csg_red,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("CSG.Normal() = %v, want %v", got, tt.want)
+			}
+			if got := tt.c.MaterialAt(tt.point); got != tt.wantMaterial {
+				t.Errorf("CSG.MaterialAt() = %v, want %v", got.Color, tt.wantMaterial.Color)
+			}
+		})
+	}
+}
+
+func TestCSG_Contains(t *testing.T) {
+	c := CSG{Operation: Difference, Left: csg_floor, Right: csg_left}
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  bool
+	}{
+		{name: "Below the floor", point: &vectors.Vector{X: 5.0, Y: -1.0, Z: 0.0}, want: true},
+		{name: "Above the floor", point: &vectors.Vector{X: 5.0, Y: 1.0, Z: 0.0}, want: false},
+		{name: "In the hole", point: &vectors.Vector{X: -1.0, Y: -1.0, Z: 0.0}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Contains(tt.point); got != tt.want {
+				t.Errorf("CSG.Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
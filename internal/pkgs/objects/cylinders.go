@@ -0,0 +1,116 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A cylinder standing on the disc at Base, running Height along the unit
+// vector Axis. Without Capped it is an open tube.
+type Cylinder struct {
+	Base     vectors.Vector
+	Axis     vectors.Vector
+	Radius   float64
+	Height   float64
+	Capped   bool
+	Material materials.Material
+}
+
+func (c Cylinder) CollideDistances(test_ray rays.Ray) []float64 {
+	// Work relative to the base, splitting everything into the part along
+	// the axis and the part across it.
+	origin := test_ray.Origin.Subtract(&c.Base)
+	origin_along := origin.Dot(&c.Axis)
+	direction_along := test_ray.Direction.Dot(&c.Axis)
+	origin_across := origin.Subtract(c.Axis.MultiplyScalar(origin_along))
+	direction_across := test_ray.Direction.Subtract(c.Axis.MultiplyScalar(direction_along))
+
+	var distances []float64
+	side := solveQuadratic(
+		direction_across.Dot(direction_across),
+		2*direction_across.Dot(origin_across),
+		origin_across.Dot(origin_across)-c.Radius*c.Radius,
+	)
+	for _, d := range side {
+		if h := origin_along + d*direction_along; h >= 0.0 && h <= c.Height {
+			distances = append(distances, d)
+		}
+	}
+	if c.Capped && direction_along != 0.0 {
+		for _, h := range []float64{0.0, c.Height} {
+			d := (h - origin_along) / direction_along
+			hit := origin_across.Add(direction_across.MultiplyScalar(d))
+			if hit.Dot(hit) <= c.Radius*c.Radius {
+				distances = append(distances, d)
+			}
+		}
+	}
+	return positiveAscending(distances)
+}
+
+func (c Cylinder) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// As CollideDistances, keeping only the nearest hit in range and the
+	// normal of the part it's on.
+	origin := test_ray.Origin.Subtract(&c.Base)
+	origin_along := origin.Dot(&c.Axis)
+	direction_along := test_ray.Direction.Dot(&c.Axis)
+	origin_across := origin.Subtract(c.Axis.MultiplyScalar(origin_along))
+	direction_across := test_ray.Direction.Subtract(c.Axis.MultiplyScalar(direction_along))
+
+	found := false
+	var normal vectors.Vector
+	side, n := quadraticRoots(
+		direction_across.Dot(direction_across),
+		2*direction_across.Dot(origin_across),
+		origin_across.Dot(origin_across)-c.Radius*c.Radius,
+	)
+	for _, d := range side[:n] {
+		if h := origin_along + d*direction_along; d > t_min && d < t_max && h >= 0.0 && h <= c.Height {
+			t_max, found = d, true
+			normal = *origin_across.Add(direction_across.MultiplyScalar(d))
+			normal.Normalise()
+		}
+	}
+	if c.Capped && direction_along != 0.0 {
+		for _, h := range [2]float64{0.0, c.Height} {
+			d := (h - origin_along) / direction_along
+			across := origin_across.Add(direction_across.MultiplyScalar(d))
+			if d > t_min && d < t_max && across.Dot(across) <= c.Radius*c.Radius {
+				t_max, found = d, true
+				normal = *c.Axis.MultiplyScalar(math.Copysign(1.0, h-c.Height/2.0))
+			}
+		}
+	}
+	if !found {
+		return Hit{}, false
+	}
+	return makeHit(test_ray, t_max, normal, c.Material), true
+}
+
+func (c Cylinder) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// Out of the side, or the cap the point is closer to than the side.
+	offset := surface_point.Subtract(&c.Base)
+	along := offset.Dot(&c.Axis)
+	across := offset.Subtract(c.Axis.MultiplyScalar(along))
+	side_dist := math.Abs(across.Magnitude() - c.Radius)
+	if c.Capped && math.Abs(along) < side_dist {
+		return c.Axis.MultiplyScalar(-1.0)
+	}
+	if c.Capped && math.Abs(along-c.Height) < side_dist {
+		normal := c.Axis
+		return &normal
+	}
+	across.Normalise()
+	return across
+}
+
+func (c Cylinder) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, c.Normal(point_of_intersection))
+}
+
+func (c Cylinder) GetMaterial() materials.Material {
+	return c.Material
+}
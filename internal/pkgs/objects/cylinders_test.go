@@ -0,0 +1,99 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestCylinder_CollideDistances(t *testing.T) {
+	// Upright, radius 1 and 2 tall, standing on the origin
+	open := Cylinder{Base: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Radius: 1.0, Height: 2.0}
+	capped := open
+	capped.Capped = true
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		c    Cylinder
+		args args
+		want []float64
+	}{
+		{
+			name: "Through the side",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 1.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{4.0, 6.0},
+		},
+		{
+			name: "Down through the caps",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.5, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{3.0, 5.0},
+		},
+		{
+			name: "Down through an open tube",
+			c:    open,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.5, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{},
+		},
+		{
+			name: "In the top, out the side",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 2.5, Z: 0.0}, &vectors.Vector{X: 1.0, Y: -1.0, Z: 0.0})},
+			want: []float64{0.70710678, 1.41421356},
+		},
+		{
+			name: "Open tube, inside wall seen from above",
+			c:    open,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 2.5, Z: 0.0}, &vectors.Vector{X: 1.0, Y: -1.0, Z: 0.0})},
+			want: []float64{1.41421356},
+		},
+		{
+			name: "Over the top",
+			c:    capped,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 2.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Tilted axis",
+			c:    Cylinder{Base: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, Axis: vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, Radius: 1.0, Height: 2.0, Capped: true},
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{9.0, 11.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Cylinder.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCylinder_Normal(t *testing.T) {
+	open := Cylinder{Base: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Radius: 1.0, Height: 2.0}
+	capped := open
+	capped.Capped = true
+	tests := []struct {
+		name  string
+		c     Cylinder
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Side", c: capped, point: &vectors.Vector{X: 0.0, Y: 1.0, Z: -1.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}},
+		{name: "Top cap", c: capped, point: &vectors.Vector{X: 0.5, Y: 2.0, Z: 0.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Bottom cap", c: capped, point: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.5}, want: &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}},
+		{name: "Open tube near the rim", c: open, point: &vectors.Vector{X: 1.0, Y: 1.99, Z: 0.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Cylinder.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package objects
+
+import (
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A flat, round disc, facing along DiscNormal
+type Disc struct {
+	Center     vectors.Vector
+	DiscNormal vectors.Vector
+	Radius     float64
+	Material   materials.Material
+}
+
+func (d Disc) CollideDistances(test_ray rays.Ray) []float64 {
+	var distances []float64
+
+	denominator := test_ray.Direction.Dot(&d.DiscNormal)
+
+	if denominator != 0 {
+		dist := d.Center.Subtract(test_ray.Origin).Dot(&d.DiscNormal) / denominator
+		hit := test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(dist))
+		offset := hit.Subtract(&d.Center)
+		if dist > 0 && offset.Dot(offset) <= d.Radius*d.Radius {
+			distances = append(distances, dist)
+		}
+	}
+
+	return distances
+}
+
+func (d Disc) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	o, direction, n := test_ray.Origin, test_ray.Direction, &d.DiscNormal
+	denominator := direction.Dot(n)
+	if denominator == 0.0 {
+		return Hit{}, false
+	}
+	t := d.Center.Subtract(o).Dot(n) / denominator
+	if t <= t_min || t >= t_max {
+		return Hit{}, false
+	}
+	point := vectors.Vector{X: o.X + t*direction.X, Y: o.Y + t*direction.Y, Z: o.Z + t*direction.Z}
+	offset := point.Subtract(&d.Center)
+	if offset.Dot(offset) > d.Radius*d.Radius {
+		return Hit{}, false
+	}
+	return Hit{
+		T:               t,
+		Point:           point,
+		GeometricNormal: d.DiscNormal,
+		ShadingNormal:   d.DiscNormal,
+		FrontFace:       denominator < 0.0,
+		Material:        d.Material,
+	}, true
+}
+
+func (d Disc) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	return &d.DiscNormal
+}
+
+func (d Disc) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, d.Normal(point_of_intersection))
+}
+
+func (d Disc) GetMaterial() materials.Material {
+	return d.Material
+}
@@ -0,0 +1,49 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestDisc_CollideDistances(t *testing.T) {
+	d := Disc{Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}, DiscNormal: vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}, Radius: 1.0}
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Middle",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{5.0},
+		},
+		{
+			name: "From behind",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.5, Y: 0.5, Z: 10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0})},
+			want: []float64{5.0},
+		},
+		{
+			name: "Outside the radius",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.8, Y: 0.8, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Parallel",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Disc.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Distance functions for SDF. Combine them as functions, e.g. math.Min of
+// two for a union, or math.Max for an intersection.
+
+func SphereDistance(center vectors.Vector, radius float64) DistanceFunction {
+	return func(point *vectors.Vector) float64 {
+		return point.Subtract(&center).Magnitude() - radius
+	}
+}
+
+func RoundedBoxDistance(center vectors.Vector, half_size vectors.Vector, radius float64) DistanceFunction {
+	// A box reaching half_size from center along each axis, with its edges
+	// and corners rounded off by radius (inside that size).
+	return func(point *vectors.Vector) float64 {
+		offset := point.Subtract(&center)
+		q := vectors.Vector{
+			X: math.Abs(offset.X) - half_size.X + radius,
+			Y: math.Abs(offset.Y) - half_size.Y + radius,
+			Z: math.Abs(offset.Z) - half_size.Z + radius,
+		}
+		outside := vectors.Vector{X: math.Max(q.X, 0.0), Y: math.Max(q.Y, 0.0), Z: math.Max(q.Z, 0.0)}
+		return outside.Magnitude() + math.Min(math.Max(q.X, math.Max(q.Y, q.Z)), 0.0) - radius
+	}
+}
+
+func SmoothUnion(a DistanceFunction, b DistanceFunction, k float64) DistanceFunction {
+	// Like math.Min(a, b), but blending them together where they're within
+	// about k of each other (polynomial smooth min).
+	return func(point *vectors.Vector) float64 {
+		da, db := a(point), b(point)
+		h := math.Max(k-math.Abs(da-db), 0.0) / k
+		return math.Min(da, db) - h*h*k/4.0
+	}
+}
+
+func MandelbulbDistance(power float64, iterations int) DistanceFunction {
+	// An estimate of the distance to the Mandelbulb fractal, with its poles
+	// along y. It fits in a box 1.2 either side of the origin; move it
+	// around with Transformed. Power 8 is the usual one.
+	const bailout float64 = 2.0
+	return func(point *vectors.Vector) float64 {
+		z := *point
+		dr := 1.0
+		r := 0.0
+		for i := 0; i < iterations; i++ {
+			r = z.Magnitude()
+			if r > bailout {
+				break
+			}
+			if r == 0.0 {
+				// Stuck at the centre, which is deep inside
+				return -1.0
+			}
+			theta := math.Acos(z.Y/r) * power
+			phi := math.Atan2(z.Z, z.X) * power
+			dr = math.Pow(r, power-1.0)*power*dr + 1.0
+			zr := math.Pow(r, power)
+			z = vectors.Vector{
+				X: zr*math.Sin(theta)*math.Cos(phi) + point.X,
+				Y: zr*math.Cos(theta) + point.Y,
+				Z: zr*math.Sin(theta)*math.Sin(phi) + point.Z,
+			}
+		}
+		if r <= 1.0 {
+			// Never escaped, so treat as inside
+			return -0.5 * math.Max(r, 1e-9) / dr
+		}
+		return 0.5 * math.Log(r) * r / dr
+	}
+}
@@ -0,0 +1,46 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestDistanceFunctions(t *testing.T) {
+	origin := vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}
+	rounded_box := RoundedBoxDistance(origin, vectors.Vector{X: 2.0, Y: 1.0, Z: 1.0}, 0.5)
+	left := SphereDistance(vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0}, 1.0)
+	right := SphereDistance(vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, 1.0)
+	blended := SmoothUnion(left, right, 0.5)
+	mandelbulb := MandelbulbDistance(8.0, 20)
+	tests := []struct {
+		name     string
+		distance DistanceFunction
+		point    *vectors.Vector
+		want     float64
+	}{
+		{name: "Sphere outside", distance: left, point: &vectors.Vector{X: -4.0, Y: 0.0, Z: 0.0}, want: 2.0},
+		{name: "Sphere inside", distance: left, point: &vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0}, want: -1.0},
+		{name: "Rounded box face", distance: rounded_box, point: &vectors.Vector{X: 3.0, Y: 0.0, Z: 0.0}, want: 1.0},
+		{name: "Rounded box inside", distance: rounded_box, point: &vectors.Vector{X: 0.0, Y: 0.5, Z: 0.0}, want: -0.5},
+		// The corner is rounded off, so further than the box's corner
+		{name: "Rounded box corner", distance: rounded_box, point: &vectors.Vector{X: 2.0, Y: 1.0, Z: 1.0}, want: 0.3660254037844386},
+		{name: "Smooth union far from the join", distance: blended, point: &vectors.Vector{X: 3.0, Y: 0.0, Z: 0.0}, want: 1.0},
+		// Above the join both spheres are the same distance away, so it fills in by k/4
+		{name: "Smooth union at the join", distance: blended, point: &vectors.Vector{X: 0.0, Y: 1.5, Z: 0.0}, want: 0.8027756377319946 - 0.125},
+		{name: "Mandelbulb centre", distance: mandelbulb, point: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, want: -1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.distance(tt.point); !utils.Close_enough(got, tt.want) {
+				t.Errorf("distance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Far from the Mandelbulb, the estimate shouldn't overshoot
+	if got := mandelbulb(&vectors.Vector{X: 0.0, Y: 0.0, Z: 3.0}); got <= 0.0 || got > 3.0-1.0 {
+		t.Errorf("MandelbulbDistance() = %v, want between 0 and 2", got)
+	}
+}
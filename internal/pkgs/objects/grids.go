@@ -0,0 +1,162 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+)
+
+// Grid is a uniform grid of boxes (voxels) over the primitives, each
+// listing the primitives which overlap it. A ray only needs testing against
+// the primitives in the cells it passes through, which it walks in order.
+// Primitives can be in several cells, so they may be visited more than once.
+type Grid struct {
+	bounds     AABB
+	resolution [3]int     // Cells along each axis
+	cell_size  [3]float64 // 0 along an axis the bounds are flat on
+	cells      []int      // Cell i holds items[cells[i]:cells[i+1]]
+	items      []int
+}
+
+const (
+	grid_density        = 3.0 // Cells per primitive
+	grid_max_resolution = 128 // Cells along any one axis
+)
+
+func BuildGrid(bounds []AABB) *Grid {
+	// Sized so there are a few cells per primitive, as close to cubes as
+	// the bounds allow.
+	g := &Grid{bounds: EmptyAABB()}
+	for _, box := range bounds {
+		g.bounds = g.bounds.Union(box)
+	}
+	if len(bounds) == 0 {
+		return g
+	}
+	extent := [3]float64{g.bounds.Max.X - g.bounds.Min.X, g.bounds.Max.Y - g.bounds.Min.Y, g.bounds.Max.Z - g.bounds.Min.Z}
+	volume := extent[0] * extent[1] * extent[2]
+	largest := math.Max(extent[0], math.Max(extent[1], extent[2]))
+	// Cells per unit length
+	per_unit := 0.0
+	if volume > 0.0 {
+		per_unit = math.Cbrt(grid_density * float64(len(bounds)) / volume)
+	} else if largest > 0.0 {
+		// Flat, or a line, so spread the cells over what's left
+		per_unit = math.Cbrt(grid_density*float64(len(bounds))) / largest
+	}
+	n_cells := 1
+	for axis := range extent {
+		resolution := int(math.Round(extent[axis] * per_unit))
+		if resolution < 1 {
+			resolution = 1
+		} else if resolution > grid_max_resolution {
+			resolution = grid_max_resolution
+		}
+		g.resolution[axis] = resolution
+		g.cell_size[axis] = extent[axis] / float64(resolution)
+		n_cells *= resolution
+	}
+
+	// Count what goes in each cell, then fill them in
+	g.cells = make([]int, n_cells+1)
+	for _, box := range bounds {
+		g.eachCell(box, func(cell int) { g.cells[cell+1]++ })
+	}
+	for i := 1; i < len(g.cells); i++ {
+		g.cells[i] += g.cells[i-1]
+	}
+	g.items = make([]int, g.cells[n_cells])
+	filled := make([]int, n_cells)
+	for primitive, box := range bounds {
+		g.eachCell(box, func(cell int) {
+			g.items[g.cells[cell]+filled[cell]] = primitive
+			filled[cell]++
+		})
+	}
+	return g
+}
+
+func (g *Grid) eachCell(box AABB, do func(cell int)) {
+	// Every cell the box overlaps.
+	low := g.cellAt([3]float64{box.Min.X, box.Min.Y, box.Min.Z})
+	high := g.cellAt([3]float64{box.Max.X, box.Max.Y, box.Max.Z})
+	for z := low[2]; z <= high[2]; z++ {
+		for y := low[1]; y <= high[1]; y++ {
+			for x := low[0]; x <= high[0]; x++ {
+				do(g.index([3]int{x, y, z}))
+			}
+		}
+	}
+}
+
+func (g *Grid) cellAt(point [3]float64) [3]int {
+	// The cell a point's in, or the nearest one to it.
+	min := [3]float64{g.bounds.Min.X, g.bounds.Min.Y, g.bounds.Min.Z}
+	var cell [3]int
+	for axis := range cell {
+		if g.cell_size[axis] > 0.0 {
+			cell[axis] = clampCell((point[axis]-min[axis])/g.cell_size[axis], g.resolution[axis])
+		}
+	}
+	return cell
+}
+
+func (g *Grid) index(cell [3]int) int {
+	return cell[0] + g.resolution[0]*(cell[1]+g.resolution[1]*cell[2])
+}
+
+func (g *Grid) Traverse(test_ray rays.Ray, t_min float64, t_max float64, visit Visitor) {
+	// Walk the cells the ray passes through in order, from where it enters
+	// the grid (Amanatides and Woo, in three dimensions). Once a hit is
+	// found inside the current cell, nothing further on can be nearer.
+	if len(g.items) == 0 {
+		return
+	}
+	t_near, t_far, ok := g.bounds.Hit(test_ray)
+	if !ok {
+		return
+	}
+	t_near = math.Max(t_near, t_min)
+	if t_near >= t_max || t_near > t_far {
+		return
+	}
+
+	origin := [3]float64{test_ray.Origin.X, test_ray.Origin.Y, test_ray.Origin.Z}
+	direction := [3]float64{test_ray.Direction.X, test_ray.Direction.Y, test_ray.Direction.Z}
+	min := [3]float64{g.bounds.Min.X, g.bounds.Min.Y, g.bounds.Min.Z}
+	var entry [3]float64
+	for axis := range entry {
+		entry[axis] = origin[axis] + direction[axis]*t_near
+	}
+	cell := g.cellAt(entry)
+	var step [3]int
+	var t_next, t_delta [3]float64
+	for axis := range cell {
+		step[axis], t_next[axis], t_delta[axis] = walkAxis(origin[axis], direction[axis], min[axis], g.cell_size[axis], cell[axis])
+	}
+
+	for {
+		index := g.index(cell)
+		for _, primitive := range g.items[g.cells[index]:g.cells[index+1]] {
+			var done bool
+			if t_max, done = visit(primitive, t_max); done {
+				return
+			}
+		}
+		axis := 0
+		if t_next[1] < t_next[axis] {
+			axis = 1
+		}
+		if t_next[2] < t_next[axis] {
+			axis = 2
+		}
+		if t_next[axis] >= t_max || t_next[axis] > t_far {
+			return
+		}
+		cell[axis] += step[axis]
+		if cell[axis] < 0 || cell[axis] >= g.resolution[axis] {
+			return
+		}
+		t_next[axis] += t_delta[axis]
+	}
+}
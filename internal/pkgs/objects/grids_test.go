@@ -0,0 +1,82 @@
+package objects
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func randomParticles(n int, r *rand.Rand) []Object {
+	// Small spheres all the same size, spread evenly over a 100 unit cube
+	particles := make([]Object, n)
+	for i := range particles {
+		particles[i] = Sphere{
+			Radius: 0.5,
+			Center: vectors.Vector{X: 100.0 * r.Float64(), Y: 100.0 * r.Float64(), Z: 100.0 * r.Float64()},
+		}
+	}
+	return particles
+}
+
+func randomFloorDiscs(n int, r *rand.Rand) []Object {
+	// All lying flat at the same height, so their bounds have no thickness
+	discs := make([]Object, n)
+	for i := range discs {
+		discs[i] = Disc{
+			Radius:     0.5 + 2.0*r.Float64(),
+			Center:     vectors.Vector{X: 100.0 * r.Float64(), Y: 50.0, Z: 100.0 * r.Float64()},
+			DiscNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		}
+	}
+	return discs
+}
+
+func TestGrid_Traverse(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name string
+		objs []Object
+	}{
+		{name: "Spheres of different sizes", objs: randomSpheres(500, r)},
+		{name: "Particles", objs: randomParticles(2000, r)},
+		{name: "Flat", objs: randomFloorDiscs(200, r)},
+		{name: "One sphere", objs: randomSpheres(1, r)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkTraverse(t, BuildGrid(boundsOf(tt.objs)), tt.objs, r)
+		})
+	}
+}
+
+func TestGrid_Traverse_empty(t *testing.T) {
+	grid := BuildGrid(nil)
+	grid.Traverse(randomRay(rand.New(rand.NewSource(1))), 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+		t.Errorf("Empty grid visited %v", i)
+		return t_max, false
+	})
+}
+
+func BenchmarkAccelerators_particles(b *testing.B) {
+	// Grids suit many primitives of about the same size
+	r := rand.New(rand.NewSource(1))
+	particles := randomParticles(10000, r)
+	test_rays := make([]rays.Ray, 1000)
+	for i := range test_rays {
+		test_rays[i] = randomRay(r)
+	}
+	kinds := map[string]AcceleratorKind{"BVH": BVHAccelerator, "Grid": GridAccelerator, "KDTree": KDTreeAccelerator}
+	for name, kind := range kinds {
+		aggregate := MakeAggregateUsing(particles, kind)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, ray := range test_rays {
+					aggregate.Intersect(ray, 0.0, math.Inf(1))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package objects
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakeGroup(children []Object, transform *vectors.Matrix, material *materials.Material) *Group {
+	// transform takes the children from the group's own space into its
+	// parent's, and may be nil to leave them where they are. material, if
+	// not nil, is used for every child without a closer override.
+	if transform == nil {
+		transform = vectors.Identity()
+	}
+	inverse, ok := transform.Inverse()
+	if !ok {
+		log.Fatal("A group needs a transform that can be undone, e.g. no scaling by 0.")
+	}
+	g := &Group{
+		Children:  children,
+		Transform: *transform,
+		Material:  material,
+		inverse:   *inverse,
+		normals:   *inverse.Transpose(),
+		aggregate: MakeAggregate(children),
+
+		untransformed: *transform == *vectors.Identity(),
+	}
+	g.bounds, g.bounded = g.computeBounds()
+	return g
+}
+
+// Group is a node in the scene graph, so that e.g. a car's wheels can be
+// moved along with it. Groups nest, and go in a scene like any other
+// object. Their hits are on the object inside that was actually hit.
+// Groups are used by pointer, and their bounds are worked out once by
+// MakeGroup, so make a new group rather than changing Children.
+type Group struct {
+	Children  []Object
+	Transform vectors.Matrix
+	Material  *materials.Material
+
+	inverse vectors.Matrix
+	normals vectors.Matrix
+	bounds  AABB // In the parent's space
+	bounded bool
+
+	aggregate *Aggregate // Of the children, in the group's space
+
+	untransformed bool // Hits on children don't need transforming
+}
+
+func (g *Group) computeBounds() (AABB, bool) {
+	b := EmptyAABB()
+	for _, child := range g.Children {
+		child_bounds, ok := BoundsOf(child)
+		if !ok {
+			return AABB{}, false
+		}
+		b = b.Union(child_bounds)
+	}
+	return b.Transform(&g.Transform), len(g.Children) > 0
+}
+
+func (g *Group) BoundingBox() (AABB, bool) {
+	return g.bounds, g.bounded
+}
+
+func (g *Group) localRay(test_ray rays.Ray) (rays.Ray, bool) {
+	// The ray in the group's own space, unless it misses the group's bounds.
+	if g.bounded {
+		if _, _, ok := g.bounds.Hit(test_ray); !ok {
+			return rays.Ray{}, false
+		}
+	}
+	return rays.Ray{
+		Origin:    g.inverse.TransformPoint(test_ray.Origin),
+		Direction: g.inverse.TransformDirection(test_ray.Direction),
+	}, true
+}
+
+func (g *Group) CollideDistances(test_ray rays.Ray) []float64 {
+	// Every child's distances together. As a solid, children which overlap
+	// don't behave like a union; use CSG for that.
+	local_ray, ok := g.localRay(test_ray)
+	if !ok {
+		return nil
+	}
+	var distances []float64
+	for _, child := range g.Children {
+		distances = append(distances, child.CollideDistances(local_ray)...)
+	}
+	return positiveAscending(distances)
+}
+
+func (g *Group) Closest(test_ray rays.Ray) (Object, float64) {
+	// The object inside the group hit first, with the group's transforms and
+	// material overrides applied so it can be shaded as it is, or nil.
+	hit, ok := g.Intersect(test_ray, 0.0, math.Inf(1))
+	if !ok {
+		return nil, 0.0
+	}
+	return hit.Object, hit.T
+}
+
+func (g *Group) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	local_ray, ok := g.localRay(test_ray)
+	if !ok {
+		return Hit{}, false
+	}
+	closest, ok := g.aggregate.Intersect(local_ray, t_min, t_max)
+	if !ok {
+		return Hit{}, false
+	}
+	// The innermost group's material wins
+	if g.Material != nil && !closest.overridden {
+		closest.Material = *g.Material
+		closest.Object = withMaterial{Object: closest.Object, Material: *g.Material}
+		closest.overridden = true
+	}
+	if !g.untransformed {
+		closest.transform(&g.Transform, &g.normals)
+		closest.Object = Transformed{Object: closest.Object, Transform: g.Transform, inverse: g.inverse, normals: g.normals}
+	}
+	return closest, true
+}
+
+func (g *Group) Occluded(test_ray rays.Ray, t_min float64, t_max float64) bool {
+	local_ray, ok := g.localRay(test_ray)
+	if !ok {
+		return false
+	}
+	_, blocked := g.aggregate.Occluded(local_ray, t_min, t_max)
+	return blocked
+}
+
+func (g *Group) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// Without a ray to go on, the child whose surface is nearest the point.
+	// Closest avoids having to guess.
+	child := g.childAt(g.inverse.TransformPoint(surface_point))
+	if child == nil {
+		return &vectors.Vector{}
+	}
+	normal := g.normals.TransformDirection(child.Normal(g.inverse.TransformPoint(surface_point)))
+	normal.Normalise()
+	return normal
+}
+
+func (g *Group) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, g.Normal(point_of_intersection))
+}
+
+func (g *Group) GetMaterial() materials.Material {
+	// Without a point to go on, the override or the first child's.
+	if g.Material != nil {
+		return *g.Material
+	}
+	if len(g.Children) == 0 {
+		return materials.Material{}
+	}
+	return g.Children[0].GetMaterial()
+}
+
+func (g *Group) MaterialAt(surface_point *vectors.Vector) materials.Material {
+	m, _ := g.materialAt(surface_point)
+	return m
+}
+
+func (g *Group) materialAt(surface_point *vectors.Vector) (materials.Material, bool) {
+	local_point := g.inverse.TransformPoint(surface_point)
+	child := g.childAt(local_point)
+	if child == nil {
+		return g.GetMaterial(), g.Material != nil
+	}
+	if group, ok := child.(*Group); ok {
+		if m, overridden := group.materialAt(local_point); overridden || g.Material == nil {
+			return m, overridden
+		}
+	}
+	if g.Material != nil {
+		return *g.Material, true
+	}
+	if coloured, ok := child.(Coloured); ok {
+		return coloured.MaterialAt(local_point), false
+	}
+	return child.GetMaterial(), false
+}
+
+func (g *Group) childAt(local_point *vectors.Vector) Object {
+	var closest Object
+	closest_dist := math.Inf(1)
+	for _, child := range g.Children {
+		if d := surfaceDistance(child, local_point); d < closest_dist {
+			closest, closest_dist = child, d
+		}
+	}
+	return closest
+}
+
+// An object shaded with its group's material instead of its own
+type withMaterial struct {
+	Object
+	Material materials.Material
+}
+
+func (w withMaterial) GetMaterial() materials.Material {
+	return w.Material
+}
+
+func (w withMaterial) MaterialAt(surface_point *vectors.Vector) materials.Material {
+	return w.Material
+}
+
+func (w withMaterial) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// Otherwise the object's own Intersect would be used, with its own material
+	hit, ok := Intersect(w.Object, test_ray, t_min, t_max)
+	if !ok {
+		return Hit{}, false
+	}
+	hit.Material = w.Material
+	hit.Object = withMaterial{Object: hit.Object, Material: w.Material}
+	return hit, true
+}
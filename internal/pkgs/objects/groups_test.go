@@ -0,0 +1,142 @@
+package objects
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A car: a body with two wheels, the wheels in their own group with a
+// material override, and the whole car moved to z = 10
+func makeTestCar(wheel_material *materials.Material, car_material *materials.Material) *Group {
+	body := Box{Min: vectors.Vector{X: -2.0, Y: 0.0, Z: -1.0}, Max: vectors.Vector{X: 2.0, Y: 1.0, Z: 1.0}, Material: materials.Material{Matte: 0.1}}
+	wheel := Sphere{Radius: 0.5, Material: materials.Material{Matte: 0.2}}
+	wheels := MakeGroup([]Object{
+		MakeTransformed(wheel, vectors.MakeTranslation(&vectors.Vector{X: -1.5, Y: 0.0, Z: 0.0})),
+		MakeTransformed(wheel, vectors.MakeTranslation(&vectors.Vector{X: 1.5, Y: 0.0, Z: 0.0})),
+	}, nil, wheel_material)
+	return MakeGroup([]Object{body, wheels}, vectors.MakeTranslation(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}), car_material)
+}
+
+func TestGroup_CollideDistances(t *testing.T) {
+	car := makeTestCar(nil, nil)
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Through the body",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.5, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{9.0, 11.0},
+		},
+		{
+			name: "Through a wheel, under the body",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 1.5, Y: -0.25, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{9.56698730, 10.43301270},
+		},
+		{
+			name: "Outside the bounds",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := car.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Group.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	b, ok := car.BoundingBox()
+	want := AABB{Min: vectors.Vector{X: -2.0, Y: -0.5, Z: 9.0}, Max: vectors.Vector{X: 2.0, Y: 1.0, Z: 11.0}}
+	if !ok || !b.Min.CloseTo(&want.Min) || !b.Max.CloseTo(&want.Max) {
+		t.Errorf("Group.BoundingBox() = %v, %v, want %v", b, ok, want)
+	}
+}
+
+func TestGroup_Closest(t *testing.T) {
+	black := materials.Material{Color: color.RGBA{0, 0, 0, 0xff}}
+	red := materials.Material{Color: color.RGBA{0xff, 0, 0, 0xff}}
+	wheel_ray := rays.MakeRay(&vectors.Vector{X: 1.5, Y: -0.25, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})
+	body_ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.5, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})
+	tests := []struct {
+		name         string
+		g            *Group
+		ray          rays.Ray
+		wantDist     float64
+		wantMaterial materials.Material
+	}{
+		{name: "No overrides", g: makeTestCar(nil, nil), ray: wheel_ray, wantDist: 9.56698730, wantMaterial: materials.Material{Matte: 0.2}},
+		{name: "Wheel override", g: makeTestCar(&black, nil), ray: wheel_ray, wantDist: 9.56698730, wantMaterial: black},
+		{name: "Car override", g: makeTestCar(nil, &red), ray: wheel_ray, wantDist: 9.56698730, wantMaterial: red},
+		{name: "Closer override wins", g: makeTestCar(&black, &red), ray: wheel_ray, wantDist: 9.56698730, wantMaterial: black},
+		{name: "Body with car override", g: makeTestCar(&black, &red), ray: body_ray, wantDist: 9.0, wantMaterial: red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, dist := tt.g.Closest(tt.ray)
+			if obj == nil || !utils.Close_enough(dist, tt.wantDist) {
+				t.Fatalf("Group.Closest() = %v, %v, want a hit at %v", obj, dist, tt.wantDist)
+			}
+			if got := obj.GetMaterial(); got != tt.wantMaterial {
+				t.Errorf("Group.Closest() material = %v, want %v", got, tt.wantMaterial)
+			}
+			point := tt.ray.Origin.Add(tt.ray.Direction.MultiplyScalar(dist))
+			if got := tt.g.MaterialAt(point); got != tt.wantMaterial {
+				t.Errorf("Group.MaterialAt() = %v, want %v", got, tt.wantMaterial)
+			}
+			if got, want := obj.Normal(point), tt.g.Normal(point); !got.CloseTo(want) {
+				t.Errorf("Group.Closest() normal = %v, but Group.Normal() = %v", got, want)
+			}
+		})
+	}
+	if obj, _ := makeTestCar(nil, nil).Closest(rays.MakeRay(&vectors.Vector{X: 0.0, Y: 5.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})); obj != nil {
+		t.Errorf("Group.Closest() = %v for a miss, want nil", obj)
+	}
+}
+
+func TestGroup_Intersect(t *testing.T) {
+	black := materials.Material{Color: color.RGBA{0, 0, 0, 0xff}}
+	red := materials.Material{Color: color.RGBA{0xff, 0, 0, 0xff}}
+	car := makeTestCar(&black, &red)
+	tests := []struct {
+		name         string
+		ray          rays.Ray
+		wantDist     float64
+		wantMaterial materials.Material
+	}{
+		{name: "Wheel", ray: rays.MakeRay(&vectors.Vector{X: 1.5, Y: -0.25, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), wantDist: 9.56698730, wantMaterial: black},
+		{name: "Body", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.5, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), wantDist: 9.0, wantMaterial: red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := car.Intersect(tt.ray, 0.0, math.Inf(1))
+			if !ok || !utils.Close_enough(hit.T, tt.wantDist) {
+				t.Fatalf("Group.Intersect() = %v, %v, want a hit at %v", hit.T, ok, tt.wantDist)
+			}
+			// In the scene's space, not the group's
+			point := tt.ray.Origin.Add(tt.ray.Direction.MultiplyScalar(tt.wantDist))
+			if !hit.Point.CloseTo(point) {
+				t.Errorf("Group.Intersect() point = %v, want %v", hit.Point, point)
+			}
+			if want := car.Normal(point); !hit.ShadingNormal.CloseTo(want) || !hit.FrontFace {
+				t.Errorf("Group.Intersect() normal = %v, front face %v, want %v from the front", hit.ShadingNormal, hit.FrontFace, want)
+			}
+			if hit.Material != tt.wantMaterial || hit.Object.GetMaterial() != tt.wantMaterial {
+				t.Errorf("Group.Intersect() material = %v, object's = %v, want %v", hit.Material, hit.Object.GetMaterial(), tt.wantMaterial)
+			}
+		})
+	}
+	if _, ok := car.Intersect(tests[0].ray, 0.0, 9.0); ok {
+		t.Errorf("Group.Intersect() found a hit beyond t_max")
+	}
+}
@@ -0,0 +1,238 @@
+package objects
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakeHeightfield(heights [][]float64, corner vectors.Vector, width float64, depth float64, vertical_scale float64, material materials.Material) *Heightfield {
+	// heights[row][column] is the height of a grid of samples, columns
+	// spread evenly across width along x and rows across depth along z, from
+	// corner. Heights are multiplied by vertical_scale and measured up from
+	// the corner.
+	if len(heights) < 2 || len(heights[0]) < 2 {
+		log.Fatal("A heightfield needs at least 2x2 heights.")
+	}
+	for _, row := range heights {
+		if len(row) != len(heights[0]) {
+			log.Fatal("Every row of a heightfield needs the same number of heights.")
+		}
+	}
+	if width <= 0.0 || depth <= 0.0 {
+		log.Fatal("A heightfield needs a positive width and depth.")
+	}
+
+	h := &Heightfield{
+		Heights:       heights,
+		Corner:        corner,
+		Width:         width,
+		Depth:         depth,
+		VerticalScale: vertical_scale,
+		Material:      material,
+	}
+	h.bounds = EmptyAABB()
+	h.normals = make([][]vectors.Vector, len(heights))
+	for j, row := range heights {
+		h.normals[j] = make([]vectors.Vector, len(row))
+		for i := range row {
+			h.bounds = h.bounds.AddPoint(h.vertex(i, j))
+			h.normals[j][i] = *h.sampleNormal(i, j)
+		}
+	}
+	return h
+}
+
+// Heightfield is terrain over a regular grid of heights. It's split into
+// two triangles per grid cell, but only the cells under a ray are tested,
+// and it's shaded with normals smoothly interpolated across the cells.
+// Heightfields are used by pointer, as they hold slices.
+type Heightfield struct {
+	Heights       [][]float64
+	Corner        vectors.Vector
+	Width         float64
+	Depth         float64
+	VerticalScale float64
+	Material      materials.Material
+
+	normals [][]vectors.Vector // one per height
+	bounds  AABB
+}
+
+func (h *Heightfield) CollideDistances(test_ray rays.Ray) []float64 {
+	var distances []float64
+	h.walk(test_ray, 0.0, math.Inf(1), func(i int, j int) bool {
+		for _, triangle := range h.cellTriangles(i, j) {
+			if dist, _, ok := intersectTriangle(test_ray, triangle[0], triangle[1], triangle[2]); ok {
+				distances = append(distances, dist)
+			}
+		}
+		return false
+	})
+	sort.Float64s(distances)
+	return dedupeDistances(distances)
+}
+
+func (h *Heightfield) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// Hits in a cell are nearer than any in the cells after it, so the walk
+	// stops at the first cell with one.
+	var closest [3]*vectors.Vector
+	found := false
+	h.walk(test_ray, t_min, t_max, func(i int, j int) bool {
+		for _, triangle := range h.cellTriangles(i, j) {
+			if dist, _, ok := intersectTriangle(test_ray, triangle[0], triangle[1], triangle[2]); ok && dist > t_min && dist < t_max {
+				closest, t_max, found = triangle, dist, true
+			}
+		}
+		return found
+	})
+	if !found {
+		return Hit{}, false
+	}
+	hit := Hit{
+		T:               t_max,
+		Point:           *test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(t_max)),
+		GeometricNormal: *faceNormal(closest[0], closest[1], closest[2]),
+		Material:        h.Material,
+	}
+	hit.ShadingNormal = *h.Normal(&hit.Point)
+	hit.FrontFace = test_ray.Direction.Dot(&hit.GeometricNormal) < 0.0
+	return hit, true
+}
+
+func (h *Heightfield) Occluded(test_ray rays.Ray, t_min float64, t_max float64) bool {
+	blocked := false
+	h.walk(test_ray, t_min, t_max, func(i int, j int) bool {
+		for _, triangle := range h.cellTriangles(i, j) {
+			if dist, _, ok := intersectTriangle(test_ray, triangle[0], triangle[1], triangle[2]); ok && dist > t_min && dist < t_max {
+				blocked = true
+			}
+		}
+		return blocked
+	})
+	return blocked
+}
+
+func (h *Heightfield) walk(test_ray rays.Ray, t_min float64, t_max float64, visit func(i int, j int) bool) {
+	// Visit the cells the ray passes over between t_min and t_max in order,
+	// from where it enters the bounds to where it leaves them (Amanatides
+	// and Woo), until visit says to stop.
+	t_near, t_far, ok := h.bounds.Hit(test_ray)
+	if !ok {
+		return
+	}
+	t_near, t_far = math.Max(t_min, t_near), math.Min(t_max, t_far)
+	if t_near > t_far {
+		return
+	}
+	columns, rows := h.cells()
+	cell_width, cell_depth := h.cellSize()
+	entry := test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(t_near))
+	i := clampCell((entry.X-h.Corner.X)/cell_width, columns)
+	j := clampCell((entry.Z-h.Corner.Z)/cell_depth, rows)
+	step_i, next_x, delta_x := walkAxis(test_ray.Origin.X, test_ray.Direction.X, h.Corner.X, cell_width, i)
+	step_j, next_z, delta_z := walkAxis(test_ray.Origin.Z, test_ray.Direction.Z, h.Corner.Z, cell_depth, j)
+
+	for i >= 0 && i < columns && j >= 0 && j < rows {
+		if visit(i, j) || math.Min(next_x, next_z) > t_far {
+			return
+		}
+		if next_x < next_z {
+			i += step_i
+			next_x += delta_x
+		} else {
+			j += step_j
+			next_z += delta_z
+		}
+	}
+}
+
+func (h *Heightfield) cellTriangles(i int, j int) [2][3]*vectors.Vector {
+	// The two triangles a cell is split into, wound to face up.
+	a, b, c, d := h.vertex(i, j), h.vertex(i+1, j), h.vertex(i+1, j+1), h.vertex(i, j+1)
+	return [2][3]*vectors.Vector{{a, c, b}, {a, d, c}}
+}
+
+func (h *Heightfield) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// The normals at the corners of the cell under the point, bilinearly
+	// interpolated.
+	columns, rows := h.cells()
+	cell_width, cell_depth := h.cellSize()
+	x := (surface_point.X - h.Corner.X) / cell_width
+	z := (surface_point.Z - h.Corner.Z) / cell_depth
+	i, j := clampCell(x, columns), clampCell(z, rows)
+	fx := math.Max(0.0, math.Min(1.0, x-float64(i)))
+	fz := math.Max(0.0, math.Min(1.0, z-float64(j)))
+
+	near := h.normals[j][i].MultiplyScalar(1.0 - fx).Add(h.normals[j][i+1].MultiplyScalar(fx))
+	far := h.normals[j+1][i].MultiplyScalar(1.0 - fx).Add(h.normals[j+1][i+1].MultiplyScalar(fx))
+	normal := near.MultiplyScalar(1.0 - fz).Add(far.MultiplyScalar(fz))
+	normal.Normalise()
+	return normal
+}
+
+func (h *Heightfield) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, h.Normal(point_of_intersection))
+}
+
+func (h *Heightfield) GetMaterial() materials.Material {
+	return h.Material
+}
+
+func (h *Heightfield) BoundingBox() (AABB, bool) {
+	return h.bounds, true
+}
+
+func (h *Heightfield) cells() (int, int) {
+	return len(h.Heights[0]) - 1, len(h.Heights) - 1
+}
+
+func (h *Heightfield) cellSize() (float64, float64) {
+	columns, rows := h.cells()
+	return h.Width / float64(columns), h.Depth / float64(rows)
+}
+
+func (h *Heightfield) vertex(i int, j int) *vectors.Vector {
+	cell_width, cell_depth := h.cellSize()
+	return &vectors.Vector{
+		X: h.Corner.X + float64(i)*cell_width,
+		Y: h.Corner.Y + h.Heights[j][i]*h.VerticalScale,
+		Z: h.Corner.Z + float64(j)*cell_depth,
+	}
+}
+
+func (h *Heightfield) sampleNormal(i int, j int) *vectors.Vector {
+	// From the slope to the neighbouring samples, or just the one
+	// neighbour at the edges.
+	columns, rows := h.cells()
+	left, right := h.vertex(clampCell(float64(i-1), columns+1), j), h.vertex(clampCell(float64(i+1), columns+1), j)
+	near, far := h.vertex(i, clampCell(float64(j-1), rows+1)), h.vertex(i, clampCell(float64(j+1), rows+1))
+	normal := &vectors.Vector{
+		X: -(right.Y - left.Y) / (right.X - left.X),
+		Y: 1.0,
+		Z: -(far.Y - near.Y) / (far.Z - near.Z),
+	}
+	normal.Normalise()
+	return normal
+}
+
+func clampCell(position float64, cells int) int {
+	return int(math.Max(0.0, math.Min(float64(cells-1), math.Floor(position))))
+}
+
+func walkAxis(origin float64, direction float64, start float64, size float64, cell int) (int, float64, float64) {
+	// Which way the ray steps between cells along one axis, the distance to
+	// the next cell boundary, and the distance between boundaries.
+	switch {
+	case direction > 0.0:
+		return 1, (start + float64(cell+1)*size - origin) / direction, size / direction
+	case direction < 0.0:
+		return -1, (start + float64(cell)*size - origin) / direction, -size / direction
+	default:
+		return 0, math.Inf(1), math.Inf(1)
+	}
+}
@@ -0,0 +1,166 @@
+package objects
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A single peak 2 high in the middle of 4x4 of flat ground, with cells 2 across
+func testHeightfield() *Heightfield {
+	heights := [][]float64{
+		{0.0, 0.0, 0.0},
+		{0.0, 1.0, 0.0},
+		{0.0, 0.0, 0.0},
+	}
+	return MakeHeightfield(heights, vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, 4.0, 4.0, 2.0, materials.Material{})
+}
+
+func TestHeightfield_CollideDistances(t *testing.T) {
+	heightfield := testHeightfield()
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Down onto the peak",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 2.0, Y: 10.0, Z: 2.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{8.0},
+		},
+		{
+			name: "Down onto a slope",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 1.5, Y: 10.0, Z: 0.5}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{9.5},
+		},
+		{
+			name: "Through the side of the peak",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 3.0, Y: 0.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{6.5, 8.5},
+		},
+		{
+			name: "Over the top",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 3.0, Y: 2.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Off the edge",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 5.0, Y: 10.0, Z: 2.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heightfield.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Heightfield.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeightfield_Normal(t *testing.T) {
+	heightfield := testHeightfield()
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Peak", point: &vectors.Vector{X: 2.0, Y: 2.0, Z: 2.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Flat corner", point: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Foot of the peak", point: &vectors.Vector{X: 0.0, Y: 0.0, Z: 2.0}, want: &vectors.Vector{X: -0.7071067811865476, Y: 0.7071067811865476, Z: 0.0}},
+		// Halfway between the last two, rather than the slope of the triangle
+		{name: "Smoothly between samples", point: &vectors.Vector{X: 1.0, Y: 1.0, Z: 2.0}, want: &vectors.Vector{X: -0.3826834323650898, Y: 0.9238795325112867, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heightfield.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Heightfield.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeightfield_Intersect(t *testing.T) {
+	heightfield := testHeightfield()
+	tests := []struct {
+		name       string
+		test_ray   rays.Ray
+		t_min      float64
+		t_max      float64
+		want_ok    bool
+		want_t     float64
+		want_point vectors.Vector
+		want_front bool
+	}{
+		{
+			name:       "Through the side of the peak",
+			test_ray:   rays.MakeRay(&vectors.Vector{X: 3.0, Y: 0.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:      100.0,
+			want_ok:    true,
+			want_t:     6.5,
+			want_point: vectors.Vector{X: 3.0, Y: 0.5, Z: 1.5},
+			want_front: true,
+		},
+		{
+			name:       "Starting past the first hit",
+			test_ray:   rays.MakeRay(&vectors.Vector{X: 3.0, Y: 0.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_min:      7.0,
+			t_max:      100.0,
+			want_ok:    true,
+			want_t:     8.5,
+			want_point: vectors.Vector{X: 3.0, Y: 0.5, Z: 3.5},
+		},
+		{
+			name:     "Stopping short",
+			test_ray: rays.MakeRay(&vectors.Vector{X: 3.0, Y: 0.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:    6.0,
+		},
+		{
+			name:     "Over the top",
+			test_ray: rays.MakeRay(&vectors.Vector{X: 3.0, Y: 2.5, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:    100.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := heightfield.Intersect(tt.test_ray, tt.t_min, tt.t_max)
+			if ok != tt.want_ok || heightfield.Occluded(tt.test_ray, tt.t_min, tt.t_max) != tt.want_ok {
+				t.Fatalf("Heightfield.Intersect() ok = %v, want %v", ok, tt.want_ok)
+			}
+			if ok && (!utils.Close_enough(hit.T, tt.want_t) || !hit.Point.CloseTo(&tt.want_point)) {
+				t.Errorf("Heightfield.Intersect() = %v at %v, want %v at %v", hit.T, hit.Point, tt.want_t, tt.want_point)
+			}
+			if ok && hit.FrontFace != tt.want_front {
+				t.Errorf("Heightfield.Intersect() FrontFace = %v, want %v", hit.FrontFace, tt.want_front)
+			}
+		})
+	}
+}
+
+func TestHeightfield_Intersect_stops_early(t *testing.T) {
+	// A long strip of ground with a wall across it near the start. Rows past
+	// the wall are taken away, so looking at them would panic.
+	heights := make([][]float64, 1000)
+	for j := range heights {
+		heights[j] = []float64{0.0, 0.0}
+	}
+	heights[3] = []float64{10.0, 10.0}
+	heightfield := MakeHeightfield(heights, vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, 1.0, 999.0, 1.0, materials.Material{})
+	for j := 5; j < len(heights); j++ {
+		heightfield.Heights[j] = nil
+	}
+	along := rays.MakeRay(&vectors.Vector{X: 0.5, Y: 1.0, Z: -1.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})
+	if hit, ok := heightfield.Intersect(along, 0.0, math.Inf(1)); !ok || hit.Point.Z > 3.0 || hit.Point.Z < 2.0 {
+		t.Errorf("Heightfield.Intersect() = %v, %v, want a hit on the near side of the wall", hit.Point, ok)
+	}
+	if !heightfield.Occluded(along, 0.0, math.Inf(1)) {
+		t.Errorf("Heightfield.Occluded() = false, want true")
+	}
+}
@@ -0,0 +1,125 @@
+package objects
+
+import (
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Hit is everything needed to shade the point where a ray first meets an
+// object, found in one go rather than by asking the object about the point
+// afterwards. Normals point out of the surface whichever side was hit;
+// FrontFace says whether that was the outside. Object is the object hit,
+// from inside any groups and ready to be shaded on its own.
+type Hit struct {
+	T               float64 // Distance along the ray, in units of its direction
+	Point           vectors.Vector
+	GeometricNormal vectors.Vector // Of the true surface
+	ShadingNormal   vectors.Vector // e.g. interpolated from a mesh's vertex normals
+	UV              UV
+	FrontFace       bool
+	Material        materials.Material
+	Object          Object // Filled in by Intersect
+
+	overridden bool // A group's material has already replaced the object's
+}
+
+// Objects which can work out their hits directly implement Intersector.
+// It's given the range of distances along the ray to look in, t_min < t <
+// t_max, and returns the nearest hit there.
+type Intersector interface {
+	Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool)
+}
+
+// Textured objects have texture coordinates over their surface.
+type Textured interface {
+	UV(surface_point *vectors.Vector) UV
+}
+
+func Intersect(obj Object, test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// The nearest hit on any object between t_min and t_max. Objects which
+	// aren't Intersectors are asked for their distances, then about the
+	// point hit.
+	if intersector, ok := obj.(Intersector); ok {
+		hit, ok := intersector.Intersect(test_ray, t_min, t_max)
+		if ok && hit.Object == nil {
+			// Left to here, where obj is already an interface, to save an allocation
+			hit.Object = obj
+		}
+		return hit, ok
+	}
+	for _, d := range obj.CollideDistances(test_ray) {
+		if d > t_min && d < t_max {
+			return hitOn(obj, test_ray, d), true
+		}
+	}
+	return Hit{}, false
+}
+
+func makeHit(test_ray rays.Ray, t float64, normal vectors.Vector, material materials.Material) Hit {
+	// A hit on a surface with the same geometric and shading normal.
+	o, d := test_ray.Origin, test_ray.Direction
+	return Hit{
+		T:               t,
+		Point:           vectors.Vector{X: o.X + t*d.X, Y: o.Y + t*d.Y, Z: o.Z + t*d.Z},
+		GeometricNormal: normal,
+		ShadingNormal:   normal,
+		FrontFace:       d.Dot(&normal) < 0.0,
+		Material:        material,
+	}
+}
+
+func hitOn(obj Object, test_ray rays.Ray, t float64) Hit {
+	point := test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(t))
+	normal := obj.Normal(point)
+	hit := Hit{
+		T:               t,
+		Point:           *point,
+		GeometricNormal: *normal,
+		ShadingNormal:   *normal,
+		FrontFace:       test_ray.Direction.Dot(normal) < 0.0,
+		Material:        obj.GetMaterial(),
+		Object:          obj,
+	}
+	if coloured, ok := obj.(Coloured); ok {
+		hit.Material = coloured.MaterialAt(point)
+	}
+	if textured, ok := obj.(Textured); ok {
+		hit.UV = textured.UV(point)
+	}
+	return hit
+}
+
+func (h *Hit) transform(transform *vectors.Matrix, normals *vectors.Matrix) {
+	// Take a hit from an object's own space into its parent's. T stays the
+	// same, as rays aren't normalised in the object's space.
+	h.Point = *transform.TransformPoint(&h.Point)
+	h.GeometricNormal = *normals.TransformDirection(&h.GeometricNormal)
+	h.GeometricNormal.Normalise()
+	h.ShadingNormal = *normals.TransformDirection(&h.ShadingNormal)
+	h.ShadingNormal.Normalise()
+}
+
+// Objects which can tell whether a ray hits them at all, between t_min and
+// t_max, faster than finding the nearest hit implement Occluder. That's all
+// shadow rays need to know.
+type Occluder interface {
+	Occluded(test_ray rays.Ray, t_min float64, t_max float64) bool
+}
+
+func Occluded(obj Object, test_ray rays.Ray, t_min float64, t_max float64) bool {
+	// Whether obj is in the way of the ray between t_min and t_max.
+	if occluder, ok := obj.(Occluder); ok {
+		return occluder.Occluded(test_ray, t_min, t_max)
+	}
+	if intersector, ok := obj.(Intersector); ok {
+		_, hit := intersector.Intersect(test_ray, t_min, t_max)
+		return hit
+	}
+	for _, d := range obj.CollideDistances(test_ray) {
+		if d > t_min && d < t_max {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package objects
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestIntersect(t *testing.T) {
+	box := Box{Min: vectors.Vector{X: -1.0, Y: -1.0, Z: 9.0}, Max: vectors.Vector{X: 1.0, Y: 1.0, Z: 11.0}, Material: materials.Material{Matte: 0.5}}
+	// Tori aren't Intersectors, so go by their distances
+	torus := Torus{Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, MajorRadius: 2.0, MinorRadius: 0.5, Material: materials.Material{Matte: 0.25}}
+	sphere := Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})
+	tests := []struct {
+		name          string
+		obj           Object
+		t_min         float64
+		t_max         float64
+		wantOk        bool
+		wantT         float64
+		wantNormal    *vectors.Vector
+		wantFrontFace bool
+	}{
+		{name: "Box", obj: box, t_max: math.Inf(1), wantOk: true, wantT: 9.0, wantNormal: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}, wantFrontFace: true},
+		{name: "Box after t_min", obj: box, t_min: 10.0, t_max: math.Inf(1), wantOk: true, wantT: 11.0, wantNormal: &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}, wantFrontFace: false},
+		{name: "Box before t_max", obj: box, t_max: 8.0, wantOk: false},
+		{name: "Torus", obj: torus, t_max: math.Inf(1), wantOk: true, wantT: 7.5, wantNormal: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}, wantFrontFace: true},
+		{name: "Torus after t_min", obj: torus, t_min: 8.0, t_max: math.Inf(1), wantOk: true, wantT: 8.5, wantNormal: &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}, wantFrontFace: false},
+		{name: "Sphere", obj: sphere, t_max: math.Inf(1), wantOk: true, wantT: 9.0, wantNormal: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}, wantFrontFace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := Intersect(tt.obj, ray, tt.t_min, tt.t_max)
+			if ok != tt.wantOk {
+				t.Fatalf("Intersect() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if !utils.Close_enough(hit.T, tt.wantT) || !hit.ShadingNormal.CloseTo(tt.wantNormal) || hit.FrontFace != tt.wantFrontFace {
+				t.Errorf("Intersect() = %v, %v, front face %v, want %v, %v, %v", hit.T, hit.ShadingNormal, hit.FrontFace, tt.wantT, tt.wantNormal, tt.wantFrontFace)
+			}
+			if hit.Object != tt.obj || hit.Material != tt.obj.GetMaterial() {
+				t.Errorf("Intersect() object = %v with %v, want %v", hit.Object, hit.Material, tt.obj)
+			}
+		})
+	}
+}
+
+// One of each of the analytic primitives which are Intersectors, around
+// (0, 0, 10)
+func testPrimitives() map[string]Object {
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+	tilted := vectors.Vector{X: 0.6, Y: 0.0, Z: -0.8}
+	return map[string]Object{
+		"Box":       Box{Min: vectors.Vector{X: -1.0, Y: -1.0, Z: 9.0}, Max: vectors.Vector{X: 1.0, Y: 1.0, Z: 11.0}},
+		"Disc":      Disc{Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, DiscNormal: tilted, Radius: 1.5},
+		"Rectangle": Rectangle{Corner: vectors.Vector{X: -1.0, Y: -1.0, Z: 10.0}, Edge1: vectors.Vector{X: 0.0, Y: 2.0, Z: 0.5}, Edge2: vectors.Vector{X: 2.0, Y: 0.0, Z: 0.0}},
+		"Cylinder":  Cylinder{Base: vectors.Vector{X: 0.0, Y: -1.0, Z: 10.0}, Axis: up, Radius: 1.0, Height: 2.0, Capped: true},
+		"Tube":      Cylinder{Base: vectors.Vector{X: 0.0, Y: -1.0, Z: 10.0}, Axis: up, Radius: 1.0, Height: 2.0},
+		"Cone":      Cone{Base: vectors.Vector{X: 0.0, Y: -1.0, Z: 10.0}, Axis: up, Radius: 1.0, Height: 2.0, Capped: true},
+	}
+}
+
+func TestIntersect_primitives(t *testing.T) {
+	// Intersect finds the same hits as CollideDistances and Normal
+	for name, obj := range testPrimitives() {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			intersector := obj.(Intersector)
+			hits := 0
+			for i := 0; i < 1000; i++ {
+				origin := vectors.Vector{X: 4.0 * (r.Float64() - 0.5), Y: 4.0 * (r.Float64() - 0.5), Z: 10.0 + 4.0*(r.Float64()-0.5)}
+				direction := vectors.Vector{X: r.Float64() - 0.5, Y: r.Float64() - 0.5, Z: r.Float64() - 0.5}
+				ray := rays.MakeRay(&origin, &direction)
+				t_min := r.Float64()
+				want_t, want_ok := 0.0, false
+				for _, d := range obj.CollideDistances(ray) {
+					if d > t_min {
+						want_t, want_ok = d, true
+						break
+					}
+				}
+				hit, ok := intersector.Intersect(ray, t_min, math.Inf(1))
+				if ok != want_ok {
+					t.Fatalf("%v.Intersect(%v, %v) ok = %v, want %v", name, ray, t_min, ok, want_ok)
+				}
+				if !ok {
+					continue
+				}
+				hits++
+				want_point := ray.Origin.Add(ray.Direction.MultiplyScalar(want_t))
+				want_normal := obj.Normal(want_point)
+				if !utils.Close_enough(hit.T, want_t) || !hit.Point.CloseTo(want_point) || !hit.ShadingNormal.CloseTo(want_normal) {
+					t.Errorf("%v.Intersect(%v, %v) = %v at %v facing %v, want %v at %v facing %v", name, ray, t_min, hit.T, hit.Point, hit.ShadingNormal, want_t, want_point, want_normal)
+				}
+				if hit.FrontFace != (ray.Direction.Dot(&hit.GeometricNormal) < 0.0) {
+					t.Errorf("%v.Intersect(%v, %v) FrontFace = %v", name, ray, t_min, hit.FrontFace)
+				}
+			}
+			if hits == 0 {
+				t.Errorf("%v.Intersect() never hit", name)
+			}
+		})
+	}
+}
+
+func TestIntersect_primitives_allocations(t *testing.T) {
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.01, Y: 0.02, Z: 1.0})
+	for name, obj := range testPrimitives() {
+		intersector := obj.(Intersector)
+		if _, ok := intersector.Intersect(ray, 0.0, math.Inf(1)); !ok {
+			t.Errorf("%v.Intersect() missed", name)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { intersector.Intersect(ray, 0.0, math.Inf(1)) }); allocs != 0 {
+			t.Errorf("%v.Intersect() allocates %v times, want 0", name, allocs)
+		}
+	}
+}
+
+func BenchmarkIntersect_primitives(b *testing.B) {
+	// Against the old way, collideAndShade
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.01, Y: 0.02, Z: 1.0})
+	for name, obj := range testPrimitives() {
+		intersector := obj.(Intersector)
+		b.Run(name+"/CollideDistances", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				collideAndShade(obj, ray)
+			}
+		})
+		b.Run(name+"/Intersect", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				intersector.Intersect(ray, 0.0, math.Inf(1))
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// One term of a polynomial, Coefficient x^X y^Y z^Z
+type Term struct {
+	Coefficient float64
+	X           int
+	Y           int
+	Z           int
+}
+
+func MakeQuadric(a, b, c, d, e, f, g, h, i, j float64, bounds *Box, material materials.Material) Implicit {
+	// The usual ten coefficients of a quadric,
+	//   a x^2 + b y^2 + c z^2 + d xy + e xz + f yz + g x + h y + i z + j = 0
+	// e.g. 1, 0, 1, 0, 0, 0, 0, -1, 0, 0 for a paraboloid opening upwards,
+	// or 1, -1, 1, 0, 0, 0, 0, 0, 0, -1 for a hyperboloid of one sheet.
+	return Implicit{
+		Terms: []Term{
+			{Coefficient: a, X: 2}, {Coefficient: b, Y: 2}, {Coefficient: c, Z: 2},
+			{Coefficient: d, X: 1, Y: 1}, {Coefficient: e, X: 1, Z: 1}, {Coefficient: f, Y: 1, Z: 1},
+			{Coefficient: g, X: 1}, {Coefficient: h, Y: 1}, {Coefficient: i, Z: 1},
+			{Coefficient: j},
+		},
+		Bounds:   bounds,
+		Material: material,
+	}
+}
+
+// The surface where the sum of Terms is zero. Quadrics and quartics are
+// what it's meant for, though any degree works. Many of these surfaces go
+// on forever, so if Bounds is set only the part inside it is kept.
+type Implicit struct {
+	Terms    []Term
+	Bounds   *Box
+	Material materials.Material
+}
+
+func (s Implicit) CollideDistances(test_ray rays.Ray) []float64 {
+	// Substituting the ray into each term gives a polynomial in the distance.
+	// Like the torus, start from where the ray enters the bounds, if we can.
+	start := 0.0
+	if s.Bounds != nil {
+		entry := s.Bounds.CollideDistances(test_ray)
+		if len(entry) == 0 {
+			return nil
+		}
+		if len(entry) == 2 {
+			start = entry[0]
+		}
+	}
+	origin := test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(start))
+	x := []float64{origin.X, test_ray.Direction.X}
+	y := []float64{origin.Y, test_ray.Direction.Y}
+	z := []float64{origin.Z, test_ray.Direction.Z}
+
+	var polynomial []float64
+	for _, term := range s.Terms {
+		product := []float64{term.Coefficient}
+		product = multiplyPolynomials(product, powerPolynomial(x, term.X))
+		product = multiplyPolynomials(product, powerPolynomial(y, term.Y))
+		product = multiplyPolynomials(product, powerPolynomial(z, term.Z))
+		polynomial = addPolynomials(polynomial, product)
+	}
+
+	var distances []float64
+	for _, root := range solvePolynomial(polynomial) {
+		d := root + start
+		if s.Bounds != nil && !s.inBounds(test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(d))) {
+			continue
+		}
+		distances = append(distances, d)
+	}
+	return positiveAscending(distances)
+}
+
+func (s Implicit) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// The gradient of the polynomial, which points towards where it's positive.
+	var normal vectors.Vector
+	for _, term := range s.Terms {
+		normal.X += term.Coefficient * float64(term.X) * pow(surface_point.X, term.X-1) * pow(surface_point.Y, term.Y) * pow(surface_point.Z, term.Z)
+		normal.Y += term.Coefficient * float64(term.Y) * pow(surface_point.X, term.X) * pow(surface_point.Y, term.Y-1) * pow(surface_point.Z, term.Z)
+		normal.Z += term.Coefficient * float64(term.Z) * pow(surface_point.X, term.X) * pow(surface_point.Y, term.Y) * pow(surface_point.Z, term.Z-1)
+	}
+	normal.Normalise()
+	return &normal
+}
+
+func (s Implicit) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, s.Normal(point_of_intersection))
+}
+
+func (s Implicit) GetMaterial() materials.Material {
+	return s.Material
+}
+
+func (s Implicit) inBounds(point *vectors.Vector) bool {
+	// With a little slack, for hits right on the edge of the bounds.
+	const slack float64 = 1e-9
+	return point.X >= s.Bounds.Min.X-slack && point.X <= s.Bounds.Max.X+slack &&
+		point.Y >= s.Bounds.Min.Y-slack && point.Y <= s.Bounds.Max.Y+slack &&
+		point.Z >= s.Bounds.Min.Z-slack && point.Z <= s.Bounds.Max.Z+slack
+}
+
+func pow(x float64, n int) float64 {
+	// x^n for small whole n, where anything to the power 0 is 1 and
+	// negative powers come from differentiating a constant, so don't count.
+	if n < 0 {
+		return 0.0
+	}
+	return math.Pow(x, float64(n))
+}
+
+func multiplyPolynomials(p []float64, q []float64) []float64 {
+	if len(p) == 0 || len(q) == 0 {
+		return nil
+	}
+	product := make([]float64, len(p)+len(q)-1)
+	for i, a := range p {
+		for j, b := range q {
+			product[i+j] += a * b
+		}
+	}
+	return product
+}
+
+func powerPolynomial(p []float64, n int) []float64 {
+	power := []float64{1.0}
+	for i := 0; i < n; i++ {
+		power = multiplyPolynomials(power, p)
+	}
+	return power
+}
+
+func addPolynomials(p []float64, q []float64) []float64 {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	sum := append([]float64(nil), p...)
+	for i, b := range q {
+		sum[i] += b
+	}
+	return sum
+}
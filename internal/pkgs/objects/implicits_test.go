@@ -0,0 +1,98 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestImplicit_CollideDistances(t *testing.T) {
+	// y = x^2 + z^2, a paraboloid opening upwards, cut off at y = 4
+	paraboloid := MakeQuadric(1.0, 0.0, 1.0, 0.0, 0.0, 0.0, 0.0, -1.0, 0.0, 0.0,
+		&Box{Min: vectors.Vector{X: -3.0, Y: -1.0, Z: -3.0}, Max: vectors.Vector{X: 3.0, Y: 4.0, Z: 3.0}}, materials.Material{})
+	unbounded := paraboloid
+	unbounded.Bounds = nil
+	// x^2 - y^2 + z^2 = 1, a hyperboloid of one sheet around the y axis
+	hyperboloid := MakeQuadric(1.0, -1.0, 1.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, -1.0, nil, materials.Material{})
+	// The same unit sphere as a quartic, (x^2 + y^2 + z^2)^2 = 1
+	quartic_sphere := Implicit{Terms: []Term{
+		{Coefficient: 1.0, X: 4}, {Coefficient: 1.0, Y: 4}, {Coefficient: 1.0, Z: 4},
+		{Coefficient: 2.0, X: 2, Y: 2}, {Coefficient: 2.0, X: 2, Z: 2}, {Coefficient: 2.0, Y: 2, Z: 2},
+		{Coefficient: -1.0},
+	}}
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		s    Implicit
+		args args
+		want []float64
+	}{
+		{
+			name: "Across the paraboloid",
+			s:    paraboloid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 1.0, Z: -10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{9.0, 11.0},
+		},
+		{
+			name: "Above the bounds",
+			s:    paraboloid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 9.0, Z: -10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Above, without bounds",
+			s:    unbounded,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 9.0, Z: -10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{7.0, 13.0},
+		},
+		{
+			name: "Down into the paraboloid",
+			s:    paraboloid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 1.0, Y: 10.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{9.0},
+		},
+		{
+			name: "Through the waist of the hyperboloid",
+			s:    hyperboloid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -5.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{4.0, 6.0},
+		},
+		{
+			name: "Quartic sphere",
+			s:    quartic_sphere,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: -5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{4.0, 6.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Implicit.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImplicit_Normal(t *testing.T) {
+	hyperboloid := MakeQuadric(1.0, -1.0, 1.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, -1.0, nil, materials.Material{})
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Waist", point: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+		{name: "Flaring out", point: &vectors.Vector{X: 0.0, Y: 1.0, Z: 1.41421356}, want: &vectors.Vector{X: 0.0, Y: -0.57735027, Z: 0.81649658}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hyperboloid.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Implicit.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
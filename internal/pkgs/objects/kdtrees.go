@@ -0,0 +1,221 @@
+package objects
+
+import (
+	"math"
+	"sort"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+)
+
+// KDTree splits space in two with a plane, and each half again, until few
+// primitives are left in each part. Unlike a BVH's boxes the parts never
+// overlap, so they can be walked strictly in order along a ray, but
+// primitives crossing a plane go in both halves and may be visited twice.
+type KDTree struct {
+	bounds AABB
+	nodes  []kdNode // The root first, and each node's lower child straight after it
+	items  []int    // Primitives, in the order the leaves refer to them
+}
+
+type kdNode struct {
+	split float64 // Interior nodes are split by a plane at split along axis
+	axis  int
+	upper int // Interior nodes' second child, above split
+	start int // Leaves hold items[start : start+count]
+	count int
+	leaf  bool
+}
+
+const (
+	kd_traversal_cost = 1.0 // Relative to testing a primitive
+	kd_empty_bonus    = 0.5 // How much cheaper a split leaving one side empty is
+	kd_max_leaf       = 2
+	kd_bad_refines    = 3 // Splits worse than a leaf allowed on the way down, in case they lead to good ones
+)
+
+func BuildKDTree(bounds []AABB) *KDTree {
+	// Split with the surface area heuristic, as for BVHs, trying planes at
+	// the sides of each primitive's box.
+	k := &KDTree{bounds: EmptyAABB()}
+	primitives := make([]int, len(bounds))
+	for i, box := range bounds {
+		primitives[i] = i
+		k.bounds = k.bounds.Union(box)
+	}
+	if len(bounds) > 0 {
+		max_depth := int(math.Round(8.0 + 1.3*math.Log2(float64(len(bounds)))))
+		k.build(bounds, primitives, k.bounds, max_depth, 0)
+	}
+	return k
+}
+
+func (k *KDTree) build(bounds []AABB, primitives []int, node_bounds AABB, depth int, bad_refines int) int {
+	index := len(k.nodes)
+	k.nodes = append(k.nodes, kdNode{})
+	leaf_cost := float64(len(primitives))
+	if len(primitives) <= kd_max_leaf || depth == 0 {
+		k.leaf(index, primitives)
+		return index
+	}
+
+	node_min := [3]float64{node_bounds.Min.X, node_bounds.Min.Y, node_bounds.Min.Z}
+	node_max := [3]float64{node_bounds.Max.X, node_bounds.Max.Y, node_bounds.Max.Z}
+	parent_area := surfaceArea(node_bounds)
+	best_cost, best_axis, best_split := math.Inf(1), -1, 0.0
+	mins := make([]float64, len(primitives))
+	maxs := make([]float64, len(primitives))
+	for axis := 0; axis < 3; axis++ {
+		if node_max[axis] <= node_min[axis] {
+			continue
+		}
+		for i, primitive := range primitives {
+			mins[i], maxs[i] = axisRange(bounds[primitive], axis)
+		}
+		sort.Float64s(mins)
+		sort.Float64s(maxs)
+		for _, candidates := range [][]float64{mins, maxs} {
+			for _, split := range candidates {
+				if split <= node_min[axis] || split >= node_max[axis] {
+					continue
+				}
+				// Those starting below the plane go below it, and those ending
+				// above it go above it, so some go in both
+				below := sort.SearchFloat64s(mins, split)
+				above := len(maxs) - sort.Search(len(maxs), func(i int) bool { return maxs[i] > split })
+				lower, upper := splitAABB(node_bounds, axis, split)
+				cost := (surfaceArea(lower)*float64(below) + surfaceArea(upper)*float64(above)) / parent_area
+				if below == 0 || above == 0 {
+					cost *= 1.0 - kd_empty_bonus
+				}
+				cost += kd_traversal_cost
+				if cost < best_cost {
+					best_cost, best_axis, best_split = cost, axis, split
+				}
+			}
+		}
+	}
+
+	if best_cost >= leaf_cost {
+		bad_refines++
+	}
+	if best_axis < 0 || bad_refines > kd_bad_refines {
+		k.leaf(index, primitives)
+		return index
+	}
+
+	var lower_primitives, upper_primitives []int
+	for _, primitive := range primitives {
+		min, max := axisRange(bounds[primitive], best_axis)
+		if min < best_split || (min == best_split && max == best_split) {
+			lower_primitives = append(lower_primitives, primitive)
+		}
+		if max > best_split {
+			upper_primitives = append(upper_primitives, primitive)
+		}
+	}
+	lower, upper := splitAABB(node_bounds, best_axis, best_split)
+	k.build(bounds, lower_primitives, lower, depth-1, bad_refines)
+	upper_index := k.build(bounds, upper_primitives, upper, depth-1, bad_refines)
+	k.nodes[index] = kdNode{split: best_split, axis: best_axis, upper: upper_index}
+	return index
+}
+
+func (k *KDTree) leaf(index int, primitives []int) {
+	k.nodes[index] = kdNode{start: len(k.items), count: len(primitives), leaf: true}
+	k.items = append(k.items, primitives...)
+}
+
+func axisRange(b AABB, axis int) (float64, float64) {
+	switch axis {
+	case 0:
+		return b.Min.X, b.Max.X
+	case 1:
+		return b.Min.Y, b.Max.Y
+	default:
+		return b.Min.Z, b.Max.Z
+	}
+}
+
+func splitAABB(b AABB, axis int, split float64) (AABB, AABB) {
+	// The parts of the box either side of a plane across axis.
+	lower, upper := b, b
+	switch axis {
+	case 0:
+		lower.Max.X, upper.Min.X = split, split
+	case 1:
+		lower.Max.Y, upper.Min.Y = split, split
+	default:
+		lower.Max.Z, upper.Min.Z = split, split
+	}
+	return lower, upper
+}
+
+// A node still to be walked, and where the ray is inside it
+type kdTodo struct {
+	node   int
+	t_near float64
+	t_far  float64
+}
+
+func (k *KDTree) Traverse(test_ray rays.Ray, t_min float64, t_max float64, visit Visitor) {
+	// Walk the leaves the ray passes through in order, splitting the part of
+	// the ray inside each node at its plane. Once a hit is found nearer than
+	// the next node, there's nothing more to do.
+	if len(k.nodes) == 0 {
+		return
+	}
+	t_near, t_far, ok := k.bounds.Hit(test_ray)
+	if !ok {
+		return
+	}
+	t_near, t_far = math.Max(t_near, t_min), math.Min(t_far, t_max)
+	if t_near > t_far {
+		return
+	}
+
+	origin := [3]float64{test_ray.Origin.X, test_ray.Origin.Y, test_ray.Origin.Z}
+	direction := [3]float64{test_ray.Direction.X, test_ray.Direction.Y, test_ray.Direction.Z}
+	var stack_space [64]kdTodo
+	stack := append(stack_space[:0], kdTodo{node: 0, t_near: t_near, t_far: t_far})
+	for len(stack) > 0 {
+		todo := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if todo.t_near >= t_max {
+			// Everything left is further along
+			return
+		}
+		// Down to the leaf the ray is in first
+		index := todo.node
+		for !k.nodes[index].leaf {
+			node := &k.nodes[index]
+			axis := node.axis
+			// The ray starts in first, or is about to go into it
+			first, second := index+1, node.upper
+			if origin[axis] > node.split || (origin[axis] == node.split && direction[axis] > 0.0) {
+				first, second = second, first
+			}
+			if direction[axis] == 0.0 {
+				index = first
+				continue
+			}
+			t_split := (node.split - origin[axis]) / direction[axis]
+			switch {
+			case t_split > todo.t_far || t_split <= 0.0:
+				index = first
+			case t_split < todo.t_near:
+				index = second
+			default:
+				stack = append(stack, kdTodo{node: second, t_near: t_split, t_far: todo.t_far})
+				index = first
+				todo.t_far = t_split
+			}
+		}
+		node := &k.nodes[index]
+		for _, primitive := range k.items[node.start : node.start+node.count] {
+			var done bool
+			if t_max, done = visit(primitive, t_max); done {
+				return
+			}
+		}
+	}
+}
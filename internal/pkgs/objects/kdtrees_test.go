@@ -0,0 +1,46 @@
+package objects
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestKDTree_Traverse(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// A few big spheres among many small ones, crossing lots of planes
+	mixed := append(randomParticles(500, r), randomSpheres(50, r)...)
+	for i := 0; i < 5; i++ {
+		mixed = append(mixed, Sphere{Radius: 20.0, Center: vectors.Vector{X: 100.0 * r.Float64(), Y: 100.0 * r.Float64(), Z: 100.0 * r.Float64()}})
+	}
+	// Nowhere to split these
+	same := make([]Object, 20)
+	for i := range same {
+		same[i] = Sphere{Radius: 1.0, Center: vectors.Vector{X: 50.0, Y: 50.0, Z: 50.0}}
+	}
+	tests := []struct {
+		name string
+		objs []Object
+	}{
+		{name: "Spheres of different sizes", objs: randomSpheres(500, r)},
+		{name: "Big and small", objs: mixed},
+		{name: "Flat", objs: randomFloorDiscs(200, r)},
+		{name: "All in the same place", objs: same},
+		{name: "One sphere", objs: randomSpheres(1, r)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkTraverse(t, BuildKDTree(boundsOf(tt.objs)), tt.objs, r)
+		})
+	}
+}
+
+func TestKDTree_Traverse_empty(t *testing.T) {
+	tree := BuildKDTree(nil)
+	tree.Traverse(randomRay(rand.New(rand.NewSource(1))), 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+		t.Errorf("Empty k-d tree visited %v", i)
+		return t_max, false
+	})
+}
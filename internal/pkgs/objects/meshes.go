@@ -0,0 +1,257 @@
+package objects
+
+import (
+	"image/color"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakeMesh(positions []vectors.Vector, normals []vectors.Vector, uvs []UV, colors []color.RGBA, faces [][3]int, material materials.Material) *Mesh {
+	m, _ := makeMesh(positions, normals, uvs, colors, faces, material, func(bounds []AABB) (*BVH, error) {
+		return BuildBVH(bounds), nil
+	})
+	return m
+}
+
+func MakeMeshCached(cache AcceleratorCache, positions []vectors.Vector, normals []vectors.Vector, uvs []UV, colors []color.RGBA, faces [][3]int, material materials.Material) (*Mesh, error) {
+	// As MakeMesh, but the mesh's BVH comes from cache when it's already been
+	// built, and is saved there otherwise. The mesh is always usable; the
+	// error only says its BVH couldn't be saved.
+	return makeMesh(positions, normals, uvs, colors, faces, material, cache.BuildBVH)
+}
+
+func makeMesh(positions []vectors.Vector, normals []vectors.Vector, uvs []UV, colors []color.RGBA, faces [][3]int, material materials.Material, build_bvh func([]AABB) (*BVH, error)) (*Mesh, error) {
+	// normals, uvs and colors may be nil, otherwise they need one entry per position.
+	if normals != nil && len(normals) != len(positions) {
+		log.Fatal("A mesh needs one normal per vertex, or none.")
+	}
+	if uvs != nil && len(uvs) != len(positions) {
+		log.Fatal("A mesh needs one UV per vertex, or none.")
+	}
+	if colors != nil && len(colors) != len(positions) {
+		log.Fatal("A mesh needs one colour per vertex, or none.")
+	}
+	for _, face := range faces {
+		for _, index := range face {
+			if index < 0 || index >= len(positions) {
+				log.Fatalf("Face vertex index %d is out of range.", index)
+			}
+		}
+	}
+	face_normals := make([]vectors.Vector, len(faces))
+	face_bounds := make([]AABB, len(faces))
+	for i, face := range faces {
+		face_normals[i] = *faceNormal(&positions[face[0]], &positions[face[1]], &positions[face[2]])
+		face_bounds[i] = EmptyAABB().AddPoint(&positions[face[0]]).AddPoint(&positions[face[1]]).AddPoint(&positions[face[2]])
+	}
+	bvh, err := build_bvh(face_bounds)
+
+	return &Mesh{
+		Positions:    positions,
+		Normals:      normals,
+		UVs:          uvs,
+		Colors:       colors,
+		Faces:        faces,
+		Material:     material,
+		face_normals: face_normals,
+		bvh:          bvh,
+	}, err
+}
+
+// Mesh is a set of triangles sharing vertices, given by index into
+// Positions. Normals, UVs and Colors are optional and indexed the same way
+// as Positions; vertex colours replace the material's colour when set.
+// Meshes are used by pointer, so they can go in a scene without copying
+// their vertices. Rays only test the faces near them, found with a BVH
+// built by MakeMesh or MakeMeshCached, so change a mesh by making a new one.
+type Mesh struct {
+	Positions []vectors.Vector
+	Normals   []vectors.Vector
+	UVs       []UV
+	Colors    []color.RGBA
+	Faces     [][3]int
+	Material  materials.Material
+
+	face_normals []vectors.Vector // only computed once per mesh
+	bvh          *BVH             // over the faces
+}
+
+func (m *Mesh) CollideDistances(test_ray rays.Ray) []float64 {
+	// Always returns distances in ascending order
+	var distances []float64
+	m.bvh.Traverse(test_ray, 0.0, math.Inf(1), func(i int, t_max float64) (float64, bool) {
+		face := m.Faces[i]
+		if d, _, ok := intersectTriangle(test_ray, &m.Positions[face[0]], &m.Positions[face[1]], &m.Positions[face[2]]); ok {
+			distances = append(distances, d)
+		}
+		return t_max, false
+	})
+	sort.Float64s(distances)
+	return dedupeDistances(distances)
+}
+
+func (m *Mesh) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// The nearest face hit, shaded with the weights of its vertices there,
+	// so there's no need to search for the face again as Normal does.
+	closest := -1
+	var closest_dist float64
+	var closest_weights [3]float64
+	m.bvh.Traverse(test_ray, t_min, t_max, func(i int, t_max float64) (float64, bool) {
+		face := m.Faces[i]
+		if d, weights, ok := intersectTriangle(test_ray, &m.Positions[face[0]], &m.Positions[face[1]], &m.Positions[face[2]]); ok && d > t_min && d < t_max {
+			closest, closest_dist, closest_weights = i, d, weights
+			return d, false
+		}
+		return t_max, false
+	})
+	if closest < 0 {
+		return Hit{}, false
+	}
+	face := m.Faces[closest]
+	hit := Hit{
+		T:               closest_dist,
+		Point:           *test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(closest_dist)),
+		GeometricNormal: m.face_normals[closest],
+		Material:        m.colourAt(face, closest_weights),
+	}
+	hit.ShadingNormal = hit.GeometricNormal
+	if m.Normals != nil {
+		hit.ShadingNormal = *interpolateNormal(closest_weights, &m.Normals[face[0]], &m.Normals[face[1]], &m.Normals[face[2]])
+	}
+	if m.UVs != nil {
+		hit.UV = interpolateUV(closest_weights, m.UVs[face[0]], m.UVs[face[1]], m.UVs[face[2]])
+	}
+	hit.FrontFace = test_ray.Direction.Dot(&hit.GeometricNormal) < 0.0
+	return hit, true
+}
+
+func (m *Mesh) Occluded(test_ray rays.Ray, t_min float64, t_max float64) bool {
+	// Stops at the first face found in the way.
+	blocked := false
+	m.bvh.Traverse(test_ray, t_min, t_max, func(i int, t_max float64) (float64, bool) {
+		face := m.Faces[i]
+		d, _, ok := intersectTriangle(test_ray, &m.Positions[face[0]], &m.Positions[face[1]], &m.Positions[face[2]])
+		blocked = ok && d > t_min && d < t_max
+		return t_max, blocked
+	})
+	return blocked
+}
+
+func (m *Mesh) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	i, weights := m.locate(surface_point)
+	if i < 0 {
+		return &vectors.Vector{}
+	}
+	if m.Normals == nil {
+		normal := m.face_normals[i]
+		return &normal
+	}
+	face := m.Faces[i]
+	return interpolateNormal(weights, &m.Normals[face[0]], &m.Normals[face[1]], &m.Normals[face[2]])
+}
+
+func (m *Mesh) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, m.Normal(point_of_intersection))
+}
+
+func (m *Mesh) GetMaterial() materials.Material {
+	return m.Material
+}
+
+func (m *Mesh) MaterialAt(surface_point *vectors.Vector) materials.Material {
+	if m.Colors == nil {
+		return m.Material
+	}
+	i, weights := m.locate(surface_point)
+	if i < 0 {
+		return m.Material
+	}
+	return m.colourAt(m.Faces[i], weights)
+}
+
+func (m *Mesh) colourAt(face [3]int, weights [3]float64) materials.Material {
+	// The material with the face's vertex colours blended by weights.
+	if m.Colors == nil {
+		return m.Material
+	}
+	var rgba [4]float64
+	for j, index := range face {
+		c := m.Colors[index]
+		rgba[0] += weights[j] * float64(c.R)
+		rgba[1] += weights[j] * float64(c.G)
+		rgba[2] += weights[j] * float64(c.B)
+		rgba[3] += weights[j] * float64(c.A)
+	}
+	for j := range rgba {
+		rgba[j] = math.Max(0.0, math.Min(255.0, math.Round(rgba[j])))
+	}
+	return m.Material.WithColor(color.RGBA{uint8(rgba[0]), uint8(rgba[1]), uint8(rgba[2]), uint8(rgba[3])})
+}
+
+func (m *Mesh) UV(surface_point *vectors.Vector) UV {
+	// Texture coordinates of a point on the mesh, zero without vertex UVs.
+	i, weights := m.locate(surface_point)
+	if i < 0 || m.UVs == nil {
+		return UV{}
+	}
+	face := m.Faces[i]
+	return interpolateUV(weights, m.UVs[face[0]], m.UVs[face[1]], m.UVs[face[2]])
+}
+
+func dedupeDistances(distances []float64) []float64 {
+	// A ray through an edge or vertex shared by several faces hits them all
+	// at the same distance, but it has only crossed the surface once.
+	const same_hit_tolerance float64 = 1e-9
+	deduped := distances[:0]
+	for i, d := range distances {
+		if i > 0 && d-deduped[len(deduped)-1] <= same_hit_tolerance*math.Max(1.0, d) {
+			continue
+		}
+		deduped = append(deduped, d)
+	}
+	return deduped
+}
+
+func (m *Mesh) locate(surface_point *vectors.Vector) (int, [3]float64) {
+	// The face a surface point lies on, and its barycentric weights there.
+	// Normal only gets a point, so this is a search for the nearest face
+	// plane which the point is inside of. Only faces whose boxes the point
+	// is in are tried, unless it's far enough off the surface to miss them.
+	const locate_margin float64 = 1e-6
+	near := EmptyAABB().AddPoint(surface_point).Expand(&vectors.Vector{X: locate_margin, Y: locate_margin, Z: locate_margin})
+	match := faceMatch{face: -1}
+	m.bvh.Overlapping(near, func(i int) { m.tryFace(surface_point, i, &match) })
+	if match.face < 0 {
+		for i := range m.Faces {
+			m.tryFace(surface_point, i, &match)
+		}
+	}
+	return match.face, match.weights
+}
+
+// The best face found for a point so far
+type faceMatch struct {
+	face    int
+	dist    float64 // From the face's plane
+	weights [3]float64
+}
+
+func (m *Mesh) tryFace(surface_point *vectors.Vector, i int, match *faceMatch) {
+	const inside_tolerance float64 = 1e-6
+	face := m.Faces[i]
+	p0 := &m.Positions[face[0]]
+	dist := math.Abs(surface_point.Subtract(p0).Dot(&m.face_normals[i]))
+	if match.face >= 0 && dist >= match.dist {
+		return
+	}
+	weights := barycentric(surface_point, p0, &m.Positions[face[1]], &m.Positions[face[2]])
+	if weights[0] < -inside_tolerance || weights[1] < -inside_tolerance || weights[2] < -inside_tolerance {
+		return
+	}
+	*match = faceMatch{face: i, dist: dist, weights: weights}
+}
@@ -0,0 +1,226 @@
+package objects
+
+import (
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func makeTestTetrahedron(normals []vectors.Vector) *Mesh {
+	// Faces wound so their normals point outwards
+	positions := []vectors.Vector{
+		{X: 0.0, Y: 0.0, Z: 0.0},
+		{X: 1.0, Y: 0.0, Z: 0.0},
+		{X: 0.0, Y: 1.0, Z: 0.0},
+		{X: 0.0, Y: 0.0, Z: 1.0},
+	}
+	faces := [][3]int{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}}
+	return MakeMesh(positions, normals, nil, nil, faces, materials.Material{})
+}
+
+func TestMesh_CollideDistances(t *testing.T) {
+	m := makeTestTetrahedron(nil)
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "In through the bottom, out through the slope",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.25, Y: 0.25, Z: -1.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{1.0, 1.5},
+		},
+		{
+			name: "Starting inside",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.25, Y: 0.25, Z: 0.25}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{0.25},
+		},
+		{
+			name: "Through a shared edge",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.5, Z: -1.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{1.0, 1.5},
+		},
+		{
+			name: "Miss",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 2.0, Y: 2.0, Z: -1.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Mesh.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMesh_Normal(t *testing.T) {
+	flat := makeTestTetrahedron(nil)
+	// Normals pointing away from the centre of the tetrahedron
+	smooth := makeTestTetrahedron([]vectors.Vector{
+		{X: -1.0, Y: -1.0, Z: -1.0},
+		{X: 1.0, Y: 0.0, Z: 0.0},
+		{X: 0.0, Y: 1.0, Z: 0.0},
+		{X: 0.0, Y: 0.0, Z: 1.0},
+	})
+	tests := []struct {
+		name  string
+		m     *Mesh
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Flat bottom face", m: flat, point: &vectors.Vector{X: 0.25, Y: 0.25, Z: 0.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}},
+		{name: "Flat slope", m: flat, point: &vectors.Vector{X: 0.25, Y: 0.25, Z: 0.5}, want: &vectors.Vector{X: 0.57735027, Y: 0.57735027, Z: 0.57735027}},
+		{name: "Smooth at a shared vertex", m: smooth, point: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Mesh.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMesh_UV(t *testing.T) {
+	positions := []vectors.Vector{{X: 0.0, Y: 0.0, Z: 0.0}, {X: 1.0, Y: 0.0, Z: 0.0}, {X: 1.0, Y: 1.0, Z: 0.0}, {X: 0.0, Y: 1.0, Z: 0.0}}
+	uvs := []UV{{U: 0.0, V: 0.0}, {U: 1.0, V: 0.0}, {U: 1.0, V: 1.0}, {U: 0.0, V: 1.0}}
+	m := MakeMesh(positions, nil, uvs, nil, [][3]int{{0, 1, 2}, {0, 2, 3}}, materials.Material{})
+	for _, p := range []vectors.Vector{{X: 0.75, Y: 0.25, Z: 0.0}, {X: 0.2, Y: 0.9, Z: 0.0}} {
+		if got := m.UV(&p); !utils.Close_enough(got.U, p.X) || !utils.Close_enough(got.V, p.Y) {
+			t.Errorf("Mesh.UV(%v) = %v, want {%v %v}", p, got, p.X, p.Y)
+		}
+	}
+}
+
+func TestMesh_MaterialAt(t *testing.T) {
+	positions := []vectors.Vector{{X: 0.0, Y: 0.0, Z: 0.0}, {X: 1.0, Y: 0.0, Z: 0.0}, {X: 0.0, Y: 1.0, Z: 0.0}}
+	colors := []color.RGBA{{0xff, 0, 0, 0xff}, {0, 0xff, 0, 0xff}, {0, 0, 0xff, 0xff}}
+	material := materials.MakeMaterial(color.RGBA{200, 200, 200, 0xff}, 0.5, 0.1, 0.25, 10, 1.0)
+	plain := MakeMesh(positions, nil, nil, nil, [][3]int{{0, 1, 2}}, material)
+	coloured := MakeMesh(positions, nil, nil, colors, [][3]int{{0, 1, 2}}, material)
+	tests := []struct {
+		name        string
+		m           *Mesh
+		point       *vectors.Vector
+		wantColor   color.RGBA
+		wantDiffuse [3]float64
+	}{
+		{name: "No vertex colours", m: plain, point: &vectors.Vector{X: 0.5, Y: 0.25, Z: 0.0}, wantColor: material.Color, wantDiffuse: material.Diffuse_consts},
+		{name: "At a vertex", m: coloured, point: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, wantColor: color.RGBA{0, 0xff, 0, 0xff}, wantDiffuse: [3]float64{0.0, 127.5, 0.0}},
+		{name: "Between vertices", m: coloured, point: &vectors.Vector{X: 0.5, Y: 0.25, Z: 0.0}, wantColor: color.RGBA{64, 128, 64, 0xff}, wantDiffuse: [3]float64{32.0, 64.0, 32.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.m.MaterialAt(tt.point)
+			if got.Color != tt.wantColor || !utils.Slice_close_enough(got.Diffuse_consts[:], tt.wantDiffuse[:]) {
+				t.Errorf("Mesh.MaterialAt() = %v with diffuse %v, want %v with %v", got.Color, got.Diffuse_consts, tt.wantColor, tt.wantDiffuse)
+			}
+			if got.Specular_consts != material.Specular_consts {
+				t.Errorf("Mesh.MaterialAt() specular = %v, want %v unchanged", got.Specular_consts, material.Specular_consts)
+			}
+		})
+	}
+}
+
+func TestMesh_Intersect(t *testing.T) {
+	// One triangle facing -z, with colours, UVs and normals tilted away from
+	// the face at its corners
+	positions := []vectors.Vector{{X: 0.0, Y: 0.0, Z: 5.0}, {X: 0.0, Y: 1.0, Z: 5.0}, {X: 1.0, Y: 0.0, Z: 5.0}}
+	normals := []vectors.Vector{{X: -1.0, Y: 0.0, Z: 0.0}, {X: 0.0, Y: 0.0, Z: -1.0}, {X: 0.0, Y: 0.0, Z: -1.0}}
+	uvs := []UV{{U: 0.0, V: 0.0}, {U: 0.0, V: 1.0}, {U: 1.0, V: 0.0}}
+	colors := []color.RGBA{{0xff, 0, 0, 0xff}, {0, 0xff, 0, 0xff}, {0, 0, 0xff, 0xff}}
+	material := materials.MakeMaterial(color.RGBA{200, 200, 200, 0xff}, 0.5, 0.1, 0.25, 10, 1.0)
+	m := MakeMesh(positions, normals, uvs, colors, [][3]int{{0, 1, 2}}, material)
+
+	hit, ok := m.Intersect(rays.MakeRay(&vectors.Vector{X: 0.25, Y: 0.5, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), 0.0, 100.0)
+	if !ok || !utils.Close_enough(hit.T, 5.0) {
+		t.Fatalf("Mesh.Intersect() = %v, %v, want a hit at 5", hit.T, ok)
+	}
+	if want := (&vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}); !hit.GeometricNormal.CloseTo(want) || !hit.FrontFace {
+		t.Errorf("Mesh.Intersect() geometric normal = %v, front face %v, want %v from the front", hit.GeometricNormal, hit.FrontFace, want)
+	}
+	// A quarter of the first vertex's normal, and three quarters of the others'
+	want_normal := &vectors.Vector{X: -0.31622777, Y: 0.0, Z: -0.9486833}
+	if !hit.ShadingNormal.CloseTo(want_normal) {
+		t.Errorf("Mesh.Intersect() shading normal = %v, want %v", hit.ShadingNormal, want_normal)
+	}
+	if want := (UV{U: 0.25, V: 0.5}); !utils.Close_enough(hit.UV.U, want.U) || !utils.Close_enough(hit.UV.V, want.V) {
+		t.Errorf("Mesh.Intersect() UV = %v, want %v", hit.UV, want)
+	}
+	if want := (color.RGBA{64, 128, 64, 0xff}); hit.Material.Color != want {
+		t.Errorf("Mesh.Intersect() colour = %v, want %v", hit.Material.Color, want)
+	}
+}
+
+func makeBumpyGrid(n int) *Mesh {
+	// An n by n grid of squares, each split in two, with vertex heights
+	// between 0 and 1
+	var positions []vectors.Vector
+	var faces [][3]int
+	for j := 0; j <= n; j++ {
+		for i := 0; i <= n; i++ {
+			positions = append(positions, vectors.Vector{X: float64(i), Y: float64((i*7+j*13)%5) / 5.0, Z: float64(j)})
+			if i < n && j < n {
+				corner := j*(n+1) + i
+				faces = append(faces, [3]int{corner, corner + n + 1, corner + 1}, [3]int{corner + 1, corner + n + 1, corner + n + 2})
+			}
+		}
+	}
+	return MakeMesh(positions, nil, nil, nil, faces, materials.Material{})
+}
+
+func TestMesh_locate(t *testing.T) {
+	// The face found through the BVH is the one a search of every face finds
+	m := makeBumpyGrid(20)
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 200; k++ {
+		ray := rays.MakeRay(&vectors.Vector{X: r.Float64() * 20.0, Y: 10.0, Z: r.Float64() * 20.0}, &vectors.Vector{X: r.Float64() - 0.5, Y: -1.0, Z: r.Float64() - 0.5})
+		hit, ok := m.Intersect(ray, 0.0, 1000.0)
+		if !ok {
+			continue
+		}
+		// A little off the surface too, which is outside the faces' boxes
+		// where they're flat
+		off := *hit.Point.Add(hit.GeometricNormal.MultiplyScalar(1e-3))
+		for _, point := range []vectors.Vector{hit.Point, off} {
+			got, _ := m.locate(&point)
+			want := faceMatch{face: -1}
+			for i := range m.Faces {
+				m.tryFace(&point, i, &want)
+			}
+			if got != want.face {
+				t.Errorf("Mesh.locate(%v) = face %d, want %d", point, got, want.face)
+			}
+		}
+	}
+}
+
+func BenchmarkMesh_Intersect(b *testing.B) {
+	// 20000 faces
+	m := makeBumpyGrid(100)
+	ray := rays.MakeRay(&vectors.Vector{X: 50.3, Y: 10.0, Z: 20.6}, &vectors.Vector{X: 0.1, Y: -1.0, Z: 0.3})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Intersect(ray, 0.0, 1000.0)
+	}
+}
+
+func BenchmarkMesh_Normal(b *testing.B) {
+	m := makeBumpyGrid(100)
+	ray := rays.MakeRay(&vectors.Vector{X: 50.3, Y: 10.0, Z: 20.6}, &vectors.Vector{X: 0.1, Y: -1.0, Z: 0.3})
+	hit, _ := m.Intersect(ray, 0.0, 1000.0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Normal(&hit.Point)
+	}
+}
@@ -13,6 +13,19 @@ type Object interface {
 	GetMaterial() materials.Material
 }
 
+// Objects whose colour varies over their surface, such as meshes with vertex
+// colours, also implement Coloured. The material it gives is used instead of
+// GetMaterial's when shading that point.
+type Coloured interface {
+	MaterialAt(surface_point *vectors.Vector) materials.Material
+}
+
+// Solids which don't close themselves off, like planes, say which points
+// are inside them. CSG needs this when a ray misses them entirely.
+type Solid interface {
+	Contains(point *vectors.Vector) bool
+}
+
 // I don't think this is done correctly, but this is the best way I could think of.
 // I don't want to be duplicating code, and this function is common among all shapes.
 func ComputeReflectedRay(incoming_ray rays.Ray, point_of_intersection *vectors.Vector, surface_normal *vectors.Vector) rays.Ray {
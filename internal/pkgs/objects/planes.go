@@ -28,6 +28,26 @@ func (p Plane) CollideDistances(test_ray rays.Ray) []float64 {
 	return distances
 }
 
+func (p Plane) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	o, d, n := test_ray.Origin, test_ray.Direction, &p.PlaneNormal
+	denominator := d.X*n.X + d.Y*n.Y + d.Z*n.Z
+	if denominator == 0.0 {
+		return Hit{}, false
+	}
+	t := ((p.Point.X-o.X)*n.X + (p.Point.Y-o.Y)*n.Y + (p.Point.Z-o.Z)*n.Z) / denominator
+	if t <= t_min || t >= t_max {
+		return Hit{}, false
+	}
+	return Hit{
+		T:               t,
+		Point:           vectors.Vector{X: o.X + t*d.X, Y: o.Y + t*d.Y, Z: o.Z + t*d.Z},
+		GeometricNormal: p.PlaneNormal,
+		ShadingNormal:   p.PlaneNormal,
+		FrontFace:       denominator < 0.0,
+		Material:        p.Material,
+	}, true
+}
+
 func (p Plane) Normal(surface_point *vectors.Vector) *vectors.Vector {
 	return &p.PlaneNormal
 }
@@ -39,3 +59,8 @@ func (p Plane) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vec
 func (p Plane) GetMaterial() materials.Material {
 	return p.Material
 }
+
+func (p Plane) Contains(point *vectors.Vector) bool {
+	// As a solid, a plane is the half-space behind it.
+	return point.Subtract(&p.Point).Dot(&p.PlaneNormal) < 0.0
+}
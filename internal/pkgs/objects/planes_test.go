@@ -0,0 +1,101 @@
+package objects
+
+import (
+	"math"
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestPlane_Intersect(t *testing.T) {
+	// The floor, at y = -10
+	plane := Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: -10.0, Z: 0.0}}
+	tests := []struct {
+		name          string
+		ray           rays.Ray
+		t_max         float64
+		wantOk        bool
+		wantPoint     *vectors.Vector
+		wantFrontFace bool
+	}{
+		{
+			name:          "From above",
+			ray:           rays.MakeRay(&vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}),
+			t_max:         100.0,
+			wantOk:        true,
+			wantPoint:     &vectors.Vector{X: 1.0, Y: -10.0, Z: 0.0},
+			wantFrontFace: true,
+		},
+		{
+			name:          "From below",
+			ray:           rays.MakeRay(&vectors.Vector{X: 0.0, Y: -20.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 1.0}),
+			t_max:         100.0,
+			wantOk:        true,
+			wantPoint:     &vectors.Vector{X: 0.0, Y: -10.0, Z: 10.0},
+			wantFrontFace: false,
+		},
+		{
+			name:   "Beyond t_max",
+			ray:    rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}),
+			t_max:  5.0,
+			wantOk: false,
+		},
+		{
+			name:   "Parallel",
+			ray:    rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:  100.0,
+			wantOk: false,
+		},
+		{
+			name:   "Away from it",
+			ray:    rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}),
+			t_max:  100.0,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := plane.Intersect(tt.ray, 0.0, tt.t_max)
+			if ok != tt.wantOk {
+				t.Fatalf("Plane.Intersect() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if !hit.Point.CloseTo(tt.wantPoint) || !utils.Close_enough(hit.T, tt.wantPoint.Subtract(tt.ray.Origin).Magnitude()) {
+				t.Errorf("Plane.Intersect() = %v at %v, want %v", hit.T, hit.Point, tt.wantPoint)
+			}
+			if !hit.ShadingNormal.CloseTo(&plane.PlaneNormal) || hit.FrontFace != tt.wantFrontFace {
+				t.Errorf("Plane.Intersect() normal = %v, front face %v, want %v, %v", hit.ShadingNormal, hit.FrontFace, plane.PlaneNormal, tt.wantFrontFace)
+			}
+		})
+	}
+}
+
+func TestPlane_Intersect_allocations(t *testing.T) {
+	plane := Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: -10.0, Z: 0.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 1.0})
+	if allocs := testing.AllocsPerRun(100, func() { plane.Intersect(ray, 0.0, math.Inf(1)) }); allocs != 0 {
+		t.Errorf("Plane.Intersect() allocates %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkPlane_CollideDistances(b *testing.B) {
+	plane := Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: -10.0, Z: 0.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 1.0})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		collideAndShade(plane, ray)
+	}
+}
+
+func BenchmarkPlane_Intersect(b *testing.B) {
+	plane := Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: -10.0, Z: 0.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 1.0})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		plane.Intersect(ray, 0.0, math.Inf(1))
+	}
+}
@@ -0,0 +1,79 @@
+package objects
+
+import (
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A finite rectangle with one corner at Corner and sides Edge1 and Edge2
+// (any parallelogram works). It faces along Edge1 x Edge2, so in our
+// left-handed scene a rectangle with Edge1 up and Edge2 to the right faces
+// back towards -z.
+type Rectangle struct {
+	Corner   vectors.Vector
+	Edge1    vectors.Vector
+	Edge2    vectors.Vector
+	Material materials.Material
+}
+
+func (r Rectangle) CollideDistances(test_ray rays.Ray) []float64 {
+	var distances []float64
+
+	normal := r.Edge1.Cross(&r.Edge2)
+	denominator := test_ray.Direction.Dot(normal)
+
+	if denominator != 0 {
+		dist := r.Corner.Subtract(test_ray.Origin).Dot(normal) / denominator
+		hit := test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(dist))
+		// hit = Corner + u Edge1 + v Edge2
+		offset := hit.Subtract(&r.Corner)
+		area := normal.Dot(normal)
+		u := offset.Cross(&r.Edge2).Dot(normal) / area
+		v := r.Edge1.Cross(offset).Dot(normal) / area
+		if dist > 0 && u >= 0.0 && u <= 1.0 && v >= 0.0 && v <= 1.0 {
+			distances = append(distances, dist)
+		}
+	}
+
+	return distances
+}
+
+func (r Rectangle) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	o, d := test_ray.Origin, test_ray.Direction
+	normal := r.Edge1.Cross(&r.Edge2)
+	denominator := d.Dot(normal)
+	if denominator == 0.0 {
+		return Hit{}, false
+	}
+	t := r.Corner.Subtract(o).Dot(normal) / denominator
+	if t <= t_min || t >= t_max {
+		return Hit{}, false
+	}
+	point := vectors.Vector{X: o.X + t*d.X, Y: o.Y + t*d.Y, Z: o.Z + t*d.Z}
+	offset := point.Subtract(&r.Corner)
+	area := normal.Dot(normal)
+	u := offset.Cross(&r.Edge2).Dot(normal) / area
+	v := r.Edge1.Cross(offset).Dot(normal) / area
+	if u < 0.0 || u > 1.0 || v < 0.0 || v > 1.0 {
+		return Hit{}, false
+	}
+	hit := Hit{T: t, Point: point, GeometricNormal: *normal, FrontFace: denominator < 0.0, Material: r.Material}
+	hit.GeometricNormal.Normalise()
+	hit.ShadingNormal = hit.GeometricNormal
+	return hit, true
+}
+
+func (r Rectangle) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	normal := r.Edge1.Cross(&r.Edge2)
+	normal.Normalise()
+	return normal
+}
+
+func (r Rectangle) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, r.Normal(point_of_intersection))
+}
+
+func (r Rectangle) GetMaterial() materials.Material {
+	return r.Material
+}
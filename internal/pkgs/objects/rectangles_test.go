@@ -0,0 +1,57 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestRectangle_CollideDistances(t *testing.T) {
+	// 4 wide and 2 high, facing -z
+	r := Rectangle{
+		Corner: vectors.Vector{X: -2.0, Y: -1.0, Z: 5.0},
+		Edge1:  vectors.Vector{X: 0.0, Y: 2.0, Z: 0.0},
+		Edge2:  vectors.Vector{X: 4.0, Y: 0.0, Z: 0.0},
+	}
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Middle",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{5.0},
+		},
+		{
+			name: "Near a corner",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 1.9, Y: 0.9, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{5.0},
+		},
+		{
+			name: "Wide",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 2.1, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "High",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 1.1, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Rectangle.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := r.Normal(&vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}); !got.CloseTo(&vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}) {
+		t.Errorf("Rectangle.Normal() = %v, want {0 0 -1}", got)
+	}
+}
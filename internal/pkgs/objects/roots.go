@@ -0,0 +1,175 @@
+package objects
+
+import (
+	"math"
+	"sort"
+)
+
+func solveQuadratic(a float64, b float64, c float64) []float64 {
+	// Real roots of a x^2 + b x + c = 0 in ascending order, a repeated root
+	// only once.
+	roots, n := quadraticRoots(a, b, c)
+	if n == 0 {
+		return nil
+	}
+	return append([]float64(nil), roots[:n]...)
+}
+
+func quadraticRoots(a float64, b float64, c float64) ([2]float64, int) {
+	// As solveQuadratic, but the roots are the first n of an array, so
+	// nothing is allocated. Uses the form which doesn't lose precision when
+	// b^2 >> 4ac.
+	if a == 0.0 {
+		if b == 0.0 {
+			return [2]float64{}, 0
+		}
+		return [2]float64{-c / b}, 1
+	}
+	delta := b*b - 4*a*c
+	if delta < 0.0 {
+		return [2]float64{}, 0
+	}
+	if delta == 0.0 {
+		return [2]float64{-b / (2 * a)}, 1
+	}
+	q := -0.5 * (b + math.Copysign(math.Sqrt(delta), b))
+	r1, r2 := q/a, c/q
+	if r1 > r2 {
+		r1, r2 = r2, r1
+	}
+	return [2]float64{r1, r2}, 2
+}
+
+func positiveAscending(distances []float64) []float64 {
+	// What CollideDistances returns: only hits in front of the ray, nearest
+	// first, and a hit on an edge between two surfaces only once.
+	var positive []float64
+	for _, d := range distances {
+		if d > 0 {
+			positive = append(positive, d)
+		}
+	}
+	sort.Float64s(positive)
+	return dedupeDistances(positive)
+}
+
+func solvePolynomial(coefficients []float64) []float64 {
+	// Real roots of coefficients[0] + coefficients[1] t + coefficients[2] t^2 + ...
+	// in ascending order, a repeated root only once. Between two turning
+	// points a polynomial only goes one way, so it crosses zero at most once
+	// there. The turning points are the roots of the derivative, found the
+	// same way, down to a quadratic which we can solve directly.
+	coefficients = trimPolynomial(coefficients)
+	degree := len(coefficients) - 1
+	switch degree {
+	case -1, 0:
+		return nil
+	case 1:
+		return []float64{-coefficients[0] / coefficients[1]}
+	case 2:
+		return solveQuadratic(coefficients[2], coefficients[1], coefficients[0])
+	}
+
+	derivative := make([]float64, degree)
+	for i := range derivative {
+		derivative[i] = float64(i+1) * coefficients[i+1]
+	}
+	// Every root is within this distance of 0 (Cauchy's bound)
+	bound := 0.0
+	for _, c := range coefficients[:degree] {
+		bound = math.Max(bound, math.Abs(c/coefficients[degree]))
+	}
+	bound += 1.0
+
+	ends := append(append([]float64{-bound}, solveCroppedTurningPoints(derivative, bound)...), bound)
+	values := make([]float64, len(ends))
+	touching := make([]bool, len(ends))
+	var roots []float64
+	for i, t := range ends {
+		values[i] = evaluatePolynomial(coefficients, t)
+		if i > 0 && i+1 < len(ends) && nearlyZero(coefficients, t, values[i]) {
+			// Just touching zero at a turning point, a repeated root
+			touching[i] = true
+			roots = append(roots, t)
+		}
+	}
+	for i := 0; i+1 < len(ends); i++ {
+		if touching[i] || touching[i+1] {
+			continue
+		}
+		if (values[i] < 0.0) != (values[i+1] < 0.0) {
+			roots = append(roots, bracketedRoot(coefficients, derivative, ends[i], ends[i+1], values[i]))
+		}
+	}
+	sort.Float64s(roots)
+	return dedupeDistances(roots)
+}
+
+func solveCroppedTurningPoints(derivative []float64, bound float64) []float64 {
+	var turning_points []float64
+	for _, t := range solvePolynomial(derivative) {
+		if t > -bound && t < bound {
+			turning_points = append(turning_points, t)
+		}
+	}
+	return turning_points
+}
+
+func trimPolynomial(coefficients []float64) []float64 {
+	// Drops leading coefficients which are zero, or as good as, compared to the rest.
+	largest := 0.0
+	for _, c := range coefficients {
+		largest = math.Max(largest, math.Abs(c))
+	}
+	for len(coefficients) > 0 && math.Abs(coefficients[len(coefficients)-1]) <= 1e-12*largest {
+		coefficients = coefficients[:len(coefficients)-1]
+	}
+	return coefficients
+}
+
+func evaluatePolynomial(coefficients []float64, t float64) float64 {
+	value := 0.0
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		value = value*t + coefficients[i]
+	}
+	return value
+}
+
+func nearlyZero(coefficients []float64, t float64, value float64) bool {
+	// Whether value is within rounding error of zero, relative to the size
+	// of the terms that went into it.
+	scale := 0.0
+	power := 1.0
+	for _, c := range coefficients {
+		scale += math.Abs(c) * power
+		power *= math.Abs(t)
+	}
+	return math.Abs(value) <= 1e-10*scale
+}
+
+func bracketedRoot(coefficients []float64, derivative []float64, lo float64, hi float64, p_lo float64) float64 {
+	// Newton's method, falling back to bisection whenever a step would leave
+	// the bracket, so it always converges.
+	t := 0.5 * (lo + hi)
+	for i := 0; i < 100; i++ {
+		p := evaluatePolynomial(coefficients, t)
+		if p == 0.0 {
+			return t
+		}
+		if (p < 0.0) == (p_lo < 0.0) {
+			lo = t
+		} else {
+			hi = t
+		}
+		step := p / evaluatePolynomial(derivative, t)
+		next := t - step
+		if math.IsNaN(next) || next <= lo || next >= hi {
+			next = 0.5 * (lo + hi)
+		}
+		if math.Abs(next-t) <= 1e-14*math.Max(1.0, math.Abs(t)) {
+			return next
+		}
+		t = next
+	}
+	return t
+}
@@ -0,0 +1,79 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+)
+
+func Test_solveQuadratic(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, c float64
+		want    []float64
+	}{
+		{name: "Two roots", a: 1.0, b: -3.0, c: 2.0, want: []float64{1.0, 2.0}},
+		{name: "Repeated root", a: 1.0, b: -2.0, c: 1.0, want: []float64{1.0}},
+		{name: "No roots", a: 1.0, b: 0.0, c: 1.0, want: []float64{}},
+		{name: "Linear", a: 0.0, b: 2.0, c: -1.0, want: []float64{0.5}},
+		{name: "Very different sizes", a: 1.0, b: -1e8, c: 1.0, want: []float64{1e-8, 1e8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := solveQuadratic(tt.a, tt.b, tt.c); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("solveQuadratic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_solvePolynomial(t *testing.T) {
+	tests := []struct {
+		name         string
+		coefficients []float64
+		want         []float64
+	}{
+		{
+			name:         "Four roots",
+			coefficients: []float64{24.0, -50.0, 35.0, -10.0, 1.0}, // (t-1)(t-2)(t-3)(t-4)
+			want:         []float64{1.0, 2.0, 3.0, 4.0},
+		},
+		{
+			name:         "Negative and positive roots",
+			coefficients: []float64{36.0, 0.0, -13.0, 0.0, 1.0}, // (t^2-4)(t^2-9)
+			want:         []float64{-3.0, -2.0, 2.0, 3.0},
+		},
+		{
+			name:         "Two repeated roots",
+			coefficients: []float64{4.0, -12.0, 13.0, -6.0, 1.0}, // (t-1)^2 (t-2)^2
+			want:         []float64{1.0, 2.0},
+		},
+		{
+			name:         "No real roots",
+			coefficients: []float64{2.0, 0.0, 3.0, 0.0, 1.0}, // (t^2+1)(t^2+2)
+			want:         []float64{},
+		},
+		{
+			name:         "Cubic",
+			coefficients: []float64{-6.0, 11.0, -6.0, 1.0},
+			want:         []float64{1.0, 2.0, 3.0},
+		},
+		{
+			name:         "Leading zeros",
+			coefficients: []float64{-2.0, 1.0, 0.0, 0.0, 0.0},
+			want:         []float64{2.0},
+		},
+		{
+			name:         "Close roots far away",
+			coefficients: []float64{1000.0 * 1000.5, -2000.5, 1.0}, // (t-1000)(t-1000.5)
+			want:         []float64{1000.0, 1000.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := solvePolynomial(tt.coefficients); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("solvePolynomial() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package objects
+
+import (
+	"log"
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A signed distance function: how far a point is from a surface, negative
+// inside it. It may underestimate, e.g. for fractals, but never overestimate.
+type DistanceFunction func(point *vectors.Vector) float64
+
+func MakeSDF(distance DistanceFunction, extent AABB, max_steps int, epsilon float64, material materials.Material) *SDF {
+	// The surface only has to be found inside extent, which keeps rays from
+	// marching forever. More steps and a smaller epsilon find finer detail,
+	// and take longer.
+	if max_steps <= 0 || epsilon <= 0.0 {
+		log.Fatal("A signed distance field needs some steps and a positive epsilon.")
+	}
+	return &SDF{
+		Distance: distance,
+		Extent:   extent,
+		MaxSteps: max_steps,
+		Epsilon:  epsilon,
+		Material: material,
+	}
+}
+
+// SDF is the surface of a signed distance function, found by sphere
+// tracing: stepping along the ray by the distance to the surface, which
+// can't overshoot it. SDFs are used by pointer, as functions can't be
+// compared.
+type SDF struct {
+	Distance DistanceFunction
+	Extent   AABB
+	MaxSteps int
+	Epsilon  float64
+	Material materials.Material
+}
+
+func (s *SDF) CollideDistances(test_ray rays.Ray) []float64 {
+	// March through the whole extent, noting every time the ray crosses the
+	// surface, in either direction.
+	t_near, t_far, ok := s.Extent.Hit(test_ray)
+	if !ok {
+		return nil
+	}
+	// Distances along the ray are in units of its direction
+	speed := test_ray.Direction.Magnitude()
+	t := math.Max(0.0, t_near)
+	step := 0
+	d := s.Distance(test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(t)))
+	// Leaving from the surface, e.g. towards a light, isn't crossing it
+	for ; step < s.MaxSteps && math.Abs(d) < s.Epsilon; step++ {
+		t += s.Epsilon / speed
+		d = s.Distance(test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(t)))
+	}
+	inside := d < 0.0
+
+	var distances []float64
+	for ; step < s.MaxSteps && t <= t_far; step++ {
+		d = s.Distance(test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(t)))
+		if (!inside && d < s.Epsilon) || (inside && d > -s.Epsilon) {
+			distances = append(distances, t)
+			inside = !inside
+			// Step far enough off the surface not to find it again
+			t += 2.0 * s.Epsilon / speed
+			continue
+		}
+		t += math.Max(math.Abs(d), s.Epsilon) / speed
+	}
+	return positiveAscending(distances)
+}
+
+func (s *SDF) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// The gradient of the distance, by central differences.
+	h := s.Epsilon
+	x, y, z := surface_point.X, surface_point.Y, surface_point.Z
+	normal := &vectors.Vector{
+		X: s.Distance(&vectors.Vector{X: x + h, Y: y, Z: z}) - s.Distance(&vectors.Vector{X: x - h, Y: y, Z: z}),
+		Y: s.Distance(&vectors.Vector{X: x, Y: y + h, Z: z}) - s.Distance(&vectors.Vector{X: x, Y: y - h, Z: z}),
+		Z: s.Distance(&vectors.Vector{X: x, Y: y, Z: z + h}) - s.Distance(&vectors.Vector{X: x, Y: y, Z: z - h}),
+	}
+	normal.Normalise()
+	return normal
+}
+
+func (s *SDF) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, s.Normal(point_of_intersection))
+}
+
+func (s *SDF) GetMaterial() materials.Material {
+	return s.Material
+}
+
+func (s *SDF) Contains(point *vectors.Vector) bool {
+	return s.Distance(point) < 0.0
+}
+
+func (s *SDF) BoundingBox() (AABB, bool) {
+	return s.Extent, true
+}
@@ -0,0 +1,110 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestSDF_CollideDistances(t *testing.T) {
+	// A sphere of radius 2 at 0, 0, 10, and two more to either side of it
+	extent := AABB{Min: vectors.Vector{X: -8.0, Y: -3.0, Z: 7.0}, Max: vectors.Vector{X: 8.0, Y: 3.0, Z: 13.0}}
+	sphere := MakeSDF(SphereDistance(vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, 2.0), extent, 200, 1e-6, materials.Material{})
+	row := MakeSDF(func(point *vectors.Vector) float64 {
+		closest := SphereDistance(vectors.Vector{X: -5.0, Y: 0.0, Z: 10.0}, 1.0)(point)
+		for _, x := range []float64{0.0, 5.0} {
+			if d := SphereDistance(vectors.Vector{X: x, Y: 0.0, Z: 10.0}, 1.0)(point); d < closest {
+				closest = d
+			}
+		}
+		return closest
+	}, extent, 200, 1e-6, materials.Material{})
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		sdf  *SDF
+		args args
+		want []float64
+	}{
+		{
+			name: "Straight through",
+			sdf:  sphere,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{8.0, 12.0},
+		},
+		{
+			name: "From inside",
+			sdf:  sphere,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{2.0},
+		},
+		{
+			name: "Leaving the surface",
+			sdf:  sphere,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 8.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Missing",
+			sdf:  sphere,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 2.5, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Missing the extent",
+			sdf:  sphere,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0})},
+			want: []float64{},
+		},
+		{
+			name: "Along a row of spheres",
+			sdf:  row,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -10.0, Y: 0.0, Z: 10.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{4.0, 6.0, 9.0, 11.0, 14.0, 16.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sdf.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("SDF.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSDF_CollideDistances_unnormalised(t *testing.T) {
+	// Transformed passes on rays with scaled directions, so distances are in
+	// units of the direction's length.
+	extent := AABB{Min: vectors.Vector{X: -3.0, Y: -3.0, Z: 7.0}, Max: vectors.Vector{X: 3.0, Y: 3.0, Z: 13.0}}
+	sphere := MakeSDF(SphereDistance(vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, 2.0), extent, 200, 1e-6, materials.Material{})
+	test_ray := rays.Ray{Origin: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Direction: &vectors.Vector{X: 0.0, Y: 0.0, Z: 2.0}}
+	if got, want := sphere.CollideDistances(test_ray), []float64{4.0, 6.0}; !utils.Slice_close_enough(got, want) {
+		t.Errorf("SDF.CollideDistances() = %v, want %v", got, want)
+	}
+}
+
+func TestSDF_Normal(t *testing.T) {
+	extent := AABB{Min: vectors.Vector{X: -3.0, Y: -3.0, Z: 7.0}, Max: vectors.Vector{X: 3.0, Y: 3.0, Z: 13.0}}
+	sphere := MakeSDF(SphereDistance(vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, 2.0), extent, 200, 1e-6, materials.Material{})
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Front", point: &vectors.Vector{X: 0.0, Y: 0.0, Z: 8.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}},
+		{name: "Top", point: &vectors.Vector{X: 0.0, Y: 2.0, Z: 10.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Diagonal", point: &vectors.Vector{X: 1.4142135623730951, Y: 0.0, Z: 11.414213562373096}, want: &vectors.Vector{X: 0.7071067811865476, Y: 0.0, Z: 0.7071067811865476}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sphere.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("SDF.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
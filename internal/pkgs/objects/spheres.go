@@ -47,6 +47,41 @@ func (s Sphere) CollideDistances(test_ray rays.Ray) []float64 {
 	return distances
 }
 
+func (s Sphere) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	// As CollideDistances, but worked through component by component so
+	// nothing is allocated.
+	o, d := test_ray.Origin, test_ray.Direction
+	ox, oy, oz := o.X-s.Center.X, o.Y-s.Center.Y, o.Z-s.Center.Z
+
+	a := d.X*d.X + d.Y*d.Y + d.Z*d.Z
+	half_b := d.X*ox + d.Y*oy + d.Z*oz
+	c := ox*ox + oy*oy + oz*oz - s.Radius*s.Radius
+
+	delta := half_b*half_b - a*c
+	if delta < 0.0 {
+		return Hit{}, false
+	}
+	root := math.Sqrt(delta)
+	t := (-half_b - root) / a
+	if t <= t_min || t >= t_max {
+		t = (-half_b + root) / a
+		if t <= t_min || t >= t_max {
+			return Hit{}, false
+		}
+	}
+
+	hit := Hit{T: t, Material: s.Material}
+	hit.Point = vectors.Vector{X: o.X + t*d.X, Y: o.Y + t*d.Y, Z: o.Z + t*d.Z}
+	hit.GeometricNormal = vectors.Vector{
+		X: (hit.Point.X - s.Center.X) / s.Radius,
+		Y: (hit.Point.Y - s.Center.Y) / s.Radius,
+		Z: (hit.Point.Z - s.Center.Z) / s.Radius,
+	}
+	hit.ShadingNormal = hit.GeometricNormal
+	hit.FrontFace = d.X*hit.GeometricNormal.X+d.Y*hit.GeometricNormal.Y+d.Z*hit.GeometricNormal.Z < 0.0
+	return hit, true
+}
+
 func (s Sphere) Normal(surface_point *vectors.Vector) *vectors.Vector {
 	normal := surface_point.Subtract(&s.Center)
 	normal.Normalise()
@@ -1,8 +1,10 @@
 package objects
 
 import (
+	"math"
 	"testing"
 
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
@@ -147,3 +149,112 @@ func TestSphere_Reflect(t *testing.T) {
 		})
 	}
 }
+
+func TestSphere_Intersect(t *testing.T) {
+	sphere := Sphere{Radius: 2.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	tests := []struct {
+		name          string
+		ray           rays.Ray
+		t_min         float64
+		t_max         float64
+		wantOk        bool
+		wantT         float64
+		wantNormal    *vectors.Vector
+		wantFrontFace bool
+	}{
+		{
+			name:          "From outside",
+			ray:           rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:         100.0,
+			wantOk:        true,
+			wantT:         8.0,
+			wantNormal:    &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0},
+			wantFrontFace: true,
+		},
+		{
+			name:          "From inside",
+			ray:           rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}),
+			t_max:         100.0,
+			wantOk:        true,
+			wantT:         2.0,
+			wantNormal:    &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+			wantFrontFace: false,
+		},
+		{
+			name:          "Past t_min, onto the far side",
+			ray:           rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_min:         9.0,
+			t_max:         100.0,
+			wantOk:        true,
+			wantT:         12.0,
+			wantNormal:    &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+			wantFrontFace: false,
+		},
+		{
+			name:   "Short of t_max",
+			ray:    rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:  5.0,
+			wantOk: false,
+		},
+		{
+			name:   "Missing",
+			ray:    rays.MakeRay(&vectors.Vector{X: 0.0, Y: 3.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			t_max:  100.0,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := sphere.Intersect(tt.ray, tt.t_min, tt.t_max)
+			if ok != tt.wantOk {
+				t.Fatalf("Sphere.Intersect() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			want_point := tt.ray.Origin.Add(tt.ray.Direction.MultiplyScalar(tt.wantT))
+			if !utils.Close_enough(hit.T, tt.wantT) || !hit.Point.CloseTo(want_point) {
+				t.Errorf("Sphere.Intersect() = %v at %v, want %v at %v", hit.T, hit.Point, tt.wantT, want_point)
+			}
+			if !hit.GeometricNormal.CloseTo(tt.wantNormal) || !hit.ShadingNormal.CloseTo(tt.wantNormal) || hit.FrontFace != tt.wantFrontFace {
+				t.Errorf("Sphere.Intersect() normals = %v, %v, front face %v, want %v, %v", hit.GeometricNormal, hit.ShadingNormal, hit.FrontFace, tt.wantNormal, tt.wantFrontFace)
+			}
+		})
+	}
+}
+
+func TestSphere_Intersect_allocations(t *testing.T) {
+	sphere := Sphere{Radius: 2.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.1, Z: 1.0})
+	if allocs := testing.AllocsPerRun(100, func() { sphere.Intersect(ray, 0.0, math.Inf(1)) }); allocs != 0 {
+		t.Errorf("Sphere.Intersect() allocates %v times, want 0", allocs)
+	}
+}
+
+// The old way of finding what a ray hits, for comparison with Intersect
+func collideAndShade(obj Object, ray rays.Ray) (*vectors.Vector, *vectors.Vector, materials.Material) {
+	dists := obj.CollideDistances(ray)
+	if len(dists) == 0 {
+		return nil, nil, materials.Material{}
+	}
+	point := ray.Origin.Add(ray.Direction.MultiplyScalar(dists[0]))
+	return point, obj.Normal(point), obj.GetMaterial()
+}
+
+func BenchmarkSphere_CollideDistances(b *testing.B) {
+	sphere := Sphere{Radius: 2.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.1, Z: 1.0})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		collideAndShade(sphere, ray)
+	}
+}
+
+func BenchmarkSphere_Intersect(b *testing.B) {
+	sphere := Sphere{Radius: 2.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	ray := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.1, Z: 1.0})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sphere.Intersect(ray, 0.0, math.Inf(1))
+	}
+}
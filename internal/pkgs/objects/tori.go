@@ -0,0 +1,81 @@
+package objects
+
+import (
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// A ring around Center, in the plane facing along the unit vector Axis.
+// MajorRadius is from the centre to the middle of the tube, MinorRadius is
+// the tube's own radius.
+type Torus struct {
+	Center      vectors.Vector
+	Axis        vectors.Vector
+	MajorRadius float64
+	MinorRadius float64
+	Material    materials.Material
+}
+
+func (t Torus) CollideDistances(test_ray rays.Ray) []float64 {
+	// Solving the quartic from far away loses a lot of precision, so first
+	// move the ray up to the sphere around the torus, and start from there.
+	bound_radius := t.MajorRadius + t.MinorRadius
+	B := test_ray.Direction
+	AC := test_ray.Origin.Subtract(&t.Center)
+	entry := solveQuadratic(B.Dot(B), 2*B.Dot(AC), AC.Dot(AC)-bound_radius*bound_radius)
+	if len(entry) < 2 || entry[1] <= 0.0 {
+		return nil
+	}
+	start := 0.0
+	if entry[0] > 0.0 {
+		start = entry[0]
+	}
+
+	// Points p on the surface satisfy
+	//   (|p|^2 + R^2 - r^2)^2 = 4 R^2 |p across the axis|^2
+	// which along the ray is a quartic in the distance.
+	origin := AC.Add(B.MultiplyScalar(start))
+	origin_across := origin.Subtract(t.Axis.MultiplyScalar(origin.Dot(&t.Axis)))
+	direction_across := B.Subtract(t.Axis.MultiplyScalar(B.Dot(&t.Axis)))
+	R2 := t.MajorRadius * t.MajorRadius
+	r2 := t.MinorRadius * t.MinorRadius
+
+	a := B.Dot(B)
+	b := 2 * B.Dot(origin)
+	c := origin.Dot(origin) + R2 - r2
+	across_a := direction_across.Dot(direction_across)
+	across_b := 2 * direction_across.Dot(origin_across)
+	across_c := origin_across.Dot(origin_across)
+
+	roots := solvePolynomial([]float64{
+		c*c - 4*R2*across_c,
+		2*b*c - 4*R2*across_b,
+		b*b + 2*a*c - 4*R2*across_a,
+		2 * a * b,
+		a * a,
+	})
+	distances := make([]float64, len(roots))
+	for i, root := range roots {
+		distances[i] = root + start
+	}
+	return positiveAscending(distances)
+}
+
+func (t Torus) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	// Away from the nearest point on the circle running through the middle of the tube.
+	offset := surface_point.Subtract(&t.Center)
+	across := offset.Subtract(t.Axis.MultiplyScalar(offset.Dot(&t.Axis)))
+	across.Normalise()
+	normal := offset.Subtract(across.MultiplyScalar(t.MajorRadius))
+	normal.Normalise()
+	return normal
+}
+
+func (t Torus) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, t.Normal(point_of_intersection))
+}
+
+func (t Torus) GetMaterial() materials.Material {
+	return t.Material
+}
@@ -0,0 +1,85 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestTorus_CollideDistances(t *testing.T) {
+	// Lying flat, 3 across the middle of the tube which is 1 thick
+	torus := Torus{Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, MajorRadius: 3.0, MinorRadius: 1.0}
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Across both sides of the ring",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{6.0, 8.0, 12.0, 14.0},
+		},
+		{
+			name: "Down through the tube",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 3.0, Y: 5.0, Z: 10.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{4.0, 6.0},
+		},
+		{
+			name: "Down through the hole",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 5.0, Z: 10.0}, &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0})},
+			want: []float64{},
+		},
+		{
+			name: "Skimming the top",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{7.0, 13.0},
+		},
+		{
+			name: "From inside the tube",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 7.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{1.0, 5.0, 7.0},
+		},
+		{
+			name: "From far away",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: -9990.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{9996.0, 9998.0, 10002.0, 10004.0},
+		},
+		{
+			name: "Missing",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 5.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := torus.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Torus.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTorus_Normal(t *testing.T) {
+	torus := Torus{Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, Axis: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, MajorRadius: 3.0, MinorRadius: 1.0}
+	tests := []struct {
+		name  string
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Outside edge", point: &vectors.Vector{X: 4.0, Y: 0.0, Z: 10.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+		{name: "Inside edge", point: &vectors.Vector{X: 0.0, Y: 0.0, Z: 12.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}},
+		{name: "Top", point: &vectors.Vector{X: 0.0, Y: 1.0, Z: 7.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := torus.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Torus.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package objects
+
+import (
+	"log"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func MakeTransformed(object Object, transform *vectors.Matrix) Transformed {
+	// transform takes the object from its own space into the scene, e.g.
+	// MakeTransformed(Sphere{Radius: 1.0}, vectors.MakeScaling(2.0, 1.0, 1.0))
+	// is an ellipsoid twice as wide as it is tall.
+	inverse, ok := transform.Inverse()
+	if !ok {
+		log.Fatal("A transformed object needs a transform that can be undone, e.g. no scaling by 0.")
+	}
+	return Transformed{
+		Object:    object,
+		Transform: *transform,
+		inverse:   *inverse,
+		normals:   *inverse.Transpose(),
+	}
+}
+
+// Transformed is an object moved, rotated or scaled by a matrix, so one
+// shape can be reused in several places. Rays are taken into the object's
+// own space rather than the object being changed, so their directions there
+// aren't normalised, but distances along them are the same as in the scene.
+type Transformed struct {
+	Object    Object
+	Transform vectors.Matrix
+
+	inverse vectors.Matrix
+	normals vectors.Matrix // Normals are transformed by the inverse transpose
+}
+
+func (t Transformed) CollideDistances(test_ray rays.Ray) []float64 {
+	return t.Object.CollideDistances(rays.Ray{
+		Origin:    t.inverse.TransformPoint(test_ray.Origin),
+		Direction: t.inverse.TransformDirection(test_ray.Direction),
+	})
+}
+
+func (t Transformed) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	hit, ok := Intersect(t.Object, rays.Ray{
+		Origin:    t.inverse.TransformPoint(test_ray.Origin),
+		Direction: t.inverse.TransformDirection(test_ray.Direction),
+	}, t_min, t_max)
+	if !ok {
+		return Hit{}, false
+	}
+	hit.transform(&t.Transform, &t.normals)
+	hit.Object = Transformed{Object: hit.Object, Transform: t.Transform, inverse: t.inverse, normals: t.normals}
+	return hit, true
+}
+
+func (t Transformed) Occluded(test_ray rays.Ray, t_min float64, t_max float64) bool {
+	return Occluded(t.Object, rays.Ray{
+		Origin:    t.inverse.TransformPoint(test_ray.Origin),
+		Direction: t.inverse.TransformDirection(test_ray.Direction),
+	}, t_min, t_max)
+}
+
+func (t Transformed) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	normal := t.normals.TransformDirection(t.Object.Normal(t.inverse.TransformPoint(surface_point)))
+	normal.Normalise()
+	return normal
+}
+
+func (t Transformed) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, t.Normal(point_of_intersection))
+}
+
+func (t Transformed) GetMaterial() materials.Material {
+	return t.Object.GetMaterial()
+}
+
+func (t Transformed) MaterialAt(surface_point *vectors.Vector) materials.Material {
+	if coloured, ok := t.Object.(Coloured); ok {
+		return coloured.MaterialAt(t.inverse.TransformPoint(surface_point))
+	}
+	return t.Object.GetMaterial()
+}
+
+func (t Transformed) Contains(point *vectors.Vector) bool {
+	return contains(t.Object, t.inverse.TransformPoint(point))
+}
@@ -0,0 +1,93 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestTransformed_CollideDistances(t *testing.T) {
+	// An ellipsoid 4 wide, 2 tall and 2 deep at z = 10
+	ellipsoid := MakeTransformed(
+		Sphere{Radius: 1.0},
+		vectors.MakeTranslation(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}).Multiply(vectors.MakeScaling(2.0, 1.0, 1.0)),
+	)
+	// The unit tetrahedron turned a quarter turn about y, so its x = 0 face faces +z
+	turned := MakeTransformed(makeTestTetrahedron(nil), vectors.MakeRotation(&vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, 90.0))
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		o    Transformed
+		args args
+		want []float64
+	}{
+		{
+			name: "Along the long axis",
+			o:    ellipsoid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -10.0, Y: 0.0, Z: 10.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{8.0, 12.0},
+		},
+		{
+			name: "Along a short axis",
+			o:    ellipsoid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{9.0, 11.0},
+		},
+		{
+			name: "Where a sphere would be hit",
+			o:    ellipsoid,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 1.5, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Turned mesh",
+			o:    turned,
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.25, Y: 0.25, Z: 5.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0})},
+			want: []float64{5.0, 5.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Transformed.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformed_Normal(t *testing.T) {
+	// Squashing a sphere tilts its normals towards the squashed axis
+	ellipsoid := MakeTransformed(Sphere{Radius: 1.0}, vectors.MakeScaling(2.0, 1.0, 1.0))
+	turned := MakeTransformed(makeTestTetrahedron(nil), vectors.MakeRotation(&vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, 90.0))
+	tests := []struct {
+		name  string
+		o     Transformed
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "End of the long axis", o: ellipsoid, point: &vectors.Vector{X: 2.0, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+		{name: "Top", o: ellipsoid, point: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, want: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}},
+		{name: "Off axis", o: ellipsoid, point: &vectors.Vector{X: 1.41421356, Y: 0.70710678, Z: 0.0}, want: &vectors.Vector{X: 0.4472136, Y: 0.89442719, Z: 0.0}},
+		{name: "Turned mesh face", o: turned, point: &vectors.Vector{X: 0.25, Y: 0.25, Z: 0.0}, want: &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Transformed.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformed_GetMaterial(t *testing.T) {
+	m := materials.Material{Matte: 0.5}
+	o := MakeTransformed(Sphere{Radius: 1.0, Material: m}, vectors.MakeTranslation(&vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}))
+	if got := o.GetMaterial(); got != m {
+		t.Errorf("Transformed.GetMaterial() = %v, want %v", got, m)
+	}
+}
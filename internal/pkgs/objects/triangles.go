@@ -0,0 +1,188 @@
+package objects
+
+import (
+	"math"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+// Texture coordinates
+type UV struct {
+	U float64
+	V float64
+}
+
+// The front of a triangle is the side its geometric normal points to, found
+// with the right hand rule on the order of its vertices.
+type Triangle struct {
+	Vertices      [3]vectors.Vector
+	VertexNormals *[3]vectors.Vector // Optional, interpolated across the face when set
+	VertexUVs     *[3]UV             // Optional
+	Material      materials.Material
+}
+
+func (tr Triangle) CollideDistances(test_ray rays.Ray) []float64 {
+	var distances []float64
+	if d, _, ok := intersectTriangle(test_ray, &tr.Vertices[0], &tr.Vertices[1], &tr.Vertices[2]); ok {
+		distances = append(distances, d)
+	}
+	return distances
+}
+
+func (tr Triangle) Intersect(test_ray rays.Ray, t_min float64, t_max float64) (Hit, bool) {
+	d, weights, ok := intersectTriangle(test_ray, &tr.Vertices[0], &tr.Vertices[1], &tr.Vertices[2])
+	if !ok || d <= t_min || d >= t_max {
+		return Hit{}, false
+	}
+	hit := Hit{
+		T:               d,
+		Point:           *test_ray.Origin.Add(test_ray.Direction.MultiplyScalar(d)),
+		GeometricNormal: *faceNormal(&tr.Vertices[0], &tr.Vertices[1], &tr.Vertices[2]),
+		Material:        tr.Material,
+	}
+	hit.ShadingNormal = hit.GeometricNormal
+	if tr.VertexNormals != nil {
+		hit.ShadingNormal = *interpolateNormal(weights, &tr.VertexNormals[0], &tr.VertexNormals[1], &tr.VertexNormals[2])
+	}
+	if tr.VertexUVs != nil {
+		hit.UV = interpolateUV(weights, tr.VertexUVs[0], tr.VertexUVs[1], tr.VertexUVs[2])
+	}
+	hit.FrontFace = test_ray.Direction.Dot(&hit.GeometricNormal) < 0.0
+	return hit, true
+}
+
+func (tr Triangle) Normal(surface_point *vectors.Vector) *vectors.Vector {
+	if tr.VertexNormals == nil {
+		return faceNormal(&tr.Vertices[0], &tr.Vertices[1], &tr.Vertices[2])
+	}
+	weights := barycentric(surface_point, &tr.Vertices[0], &tr.Vertices[1], &tr.Vertices[2])
+	return interpolateNormal(weights, &tr.VertexNormals[0], &tr.VertexNormals[1], &tr.VertexNormals[2])
+}
+
+func (tr Triangle) Reflect(incoming_ray rays.Ray, point_of_intersection *vectors.Vector) rays.Ray {
+	return ComputeReflectedRay(incoming_ray, point_of_intersection, tr.Normal(point_of_intersection))
+}
+
+func (tr Triangle) GetMaterial() materials.Material {
+	return tr.Material
+}
+
+func (tr Triangle) UV(surface_point *vectors.Vector) UV {
+	// Texture coordinates of a point on the triangle, zero without vertex UVs.
+	if tr.VertexUVs == nil {
+		return UV{}
+	}
+	weights := barycentric(surface_point, &tr.Vertices[0], &tr.Vertices[1], &tr.Vertices[2])
+	return interpolateUV(weights, tr.VertexUVs[0], tr.VertexUVs[1], tr.VertexUVs[2])
+}
+
+func component(v *vectors.Vector, axis int) float64 {
+	switch axis {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	}
+	return v.Z
+}
+
+// intersectTriangle is the watertight ray/triangle test of Woop, Benthin and
+// Wald (2013). Rays through a shared edge or vertex always hit at least one
+// of the triangles, so meshes don't leak at their seams. Also returns the
+// barycentric weights of p0, p1 and p2 at the hit.
+func intersectTriangle(test_ray rays.Ray, p0 *vectors.Vector, p1 *vectors.Vector, p2 *vectors.Vector) (float64, [3]float64, bool) {
+	var weights [3]float64
+	dir := test_ray.Direction
+
+	// Shear and scale so the ray runs along +Z from the origin, picking Z as
+	// the largest component of the direction for precision.
+	kz := 0
+	if math.Abs(dir.Y) > math.Abs(component(dir, kz)) {
+		kz = 1
+	}
+	if math.Abs(dir.Z) > math.Abs(component(dir, kz)) {
+		kz = 2
+	}
+	kx := (kz + 1) % 3
+	ky := (kx + 1) % 3
+	if component(dir, kz) < 0.0 {
+		// Keep the winding the same
+		kx, ky = ky, kx
+	}
+	sx := component(dir, kx) / component(dir, kz)
+	sy := component(dir, ky) / component(dir, kz)
+	sz := 1.0 / component(dir, kz)
+
+	a := p0.Subtract(test_ray.Origin)
+	b := p1.Subtract(test_ray.Origin)
+	c := p2.Subtract(test_ray.Origin)
+	ax := component(a, kx) - sx*component(a, kz)
+	ay := component(a, ky) - sy*component(a, kz)
+	bx := component(b, kx) - sx*component(b, kz)
+	by := component(b, ky) - sy*component(b, kz)
+	cx := component(c, kx) - sx*component(c, kz)
+	cy := component(c, ky) - sy*component(c, kz)
+
+	// Scaled barycentric coordinates, all the same sign if the ray passes through
+	u := cx*by - cy*bx
+	v := ax*cy - ay*cx
+	w := bx*ay - by*ax
+	if (u < 0.0 || v < 0.0 || w < 0.0) && (u > 0.0 || v > 0.0 || w > 0.0) {
+		return 0.0, weights, false
+	}
+	det := u + v + w
+	if det == 0.0 {
+		return 0.0, weights, false
+	}
+
+	az := sz * component(a, kz)
+	bz := sz * component(b, kz)
+	cz := sz * component(c, kz)
+	d := (u*az + v*bz + w*cz) / det
+	if d <= 0.0 {
+		return 0.0, weights, false
+	}
+	weights = [3]float64{u / det, v / det, w / det}
+	return d, weights, true
+}
+
+func faceNormal(p0 *vectors.Vector, p1 *vectors.Vector, p2 *vectors.Vector) *vectors.Vector {
+	normal := p1.Subtract(p0).Cross(p2.Subtract(p0))
+	normal.Normalise()
+	return normal
+}
+
+func barycentric(point *vectors.Vector, p0 *vectors.Vector, p1 *vectors.Vector, p2 *vectors.Vector) [3]float64 {
+	// Weights of p0, p1 and p2 for the projection of point onto their plane.
+	e1 := p1.Subtract(p0)
+	e2 := p2.Subtract(p0)
+	ep := point.Subtract(p0)
+	d11 := e1.Dot(e1)
+	d12 := e1.Dot(e2)
+	d22 := e2.Dot(e2)
+	dp1 := ep.Dot(e1)
+	dp2 := ep.Dot(e2)
+	denominator := d11*d22 - d12*d12
+	if denominator == 0.0 {
+		// Degenerate triangle
+		return [3]float64{1.0, 0.0, 0.0}
+	}
+	w1 := (d22*dp1 - d12*dp2) / denominator
+	w2 := (d11*dp2 - d12*dp1) / denominator
+	return [3]float64{1.0 - w1 - w2, w1, w2}
+}
+
+func interpolateNormal(weights [3]float64, n0 *vectors.Vector, n1 *vectors.Vector, n2 *vectors.Vector) *vectors.Vector {
+	normal := n0.MultiplyScalar(weights[0]).Add(n1.MultiplyScalar(weights[1])).Add(n2.MultiplyScalar(weights[2]))
+	normal.Normalise()
+	return normal
+}
+
+func interpolateUV(weights [3]float64, uv0 UV, uv1 UV, uv2 UV) UV {
+	return UV{
+		U: weights[0]*uv0.U + weights[1]*uv1.U + weights[2]*uv2.U,
+		V: weights[0]*uv0.V + weights[1]*uv1.V + weights[2]*uv2.V,
+	}
+}
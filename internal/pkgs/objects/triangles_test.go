@@ -0,0 +1,114 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
+)
+
+func TestTriangle_CollideDistances(t *testing.T) {
+	tr := Triangle{Vertices: [3]vectors.Vector{
+		{X: -1.0, Y: -1.0, Z: 5.0},
+		{X: 1.0, Y: -1.0, Z: 5.0},
+		{X: 0.0, Y: 1.0, Z: 5.0},
+	}}
+	type args struct {
+		test_ray rays.Ray
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{
+			name: "Straight through the middle",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{5.0},
+		},
+		{
+			name: "From behind",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0})},
+			want: []float64{5.0},
+		},
+		{
+			name: "At an angle",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -3.0, Y: 0.0, Z: 2.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 1.0})},
+			want: []float64{4.242640687},
+		},
+		{
+			name: "Miss to the side",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 2.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Pointing away",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0})},
+			want: []float64{},
+		},
+		{
+			name: "Parallel to the plane",
+			args: args{test_ray: rays.MakeRay(&vectors.Vector{X: -5.0, Y: 0.0, Z: 5.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})},
+			want: []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tr.CollideDistances(tt.args.test_ray); !utils.Slice_close_enough(got, tt.want) {
+				t.Errorf("Triangle.CollideDistances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriangle_Watertight(t *testing.T) {
+	// Two triangles sharing the diagonal of a square, and rays right along it
+	a := Triangle{Vertices: [3]vectors.Vector{{X: 0.0, Y: 0.0, Z: 1.0}, {X: 1.0, Y: 0.0, Z: 1.0}, {X: 1.0, Y: 1.0, Z: 1.0}}}
+	b := Triangle{Vertices: [3]vectors.Vector{{X: 0.0, Y: 0.0, Z: 1.0}, {X: 1.0, Y: 1.0, Z: 1.0}, {X: 0.0, Y: 1.0, Z: 1.0}}}
+	for i := 0; i <= 100; i++ {
+		s := float64(i) / 100.0
+		ray := rays.MakeRay(&vectors.Vector{X: 0.3, Y: 0.7, Z: -2.0}, &vectors.Vector{X: s - 0.3, Y: s - 0.7, Z: 3.0})
+		if len(a.CollideDistances(ray)) == 0 && len(b.CollideDistances(ray)) == 0 {
+			t.Errorf("Ray through the shared edge at %v missed both triangles", s)
+		}
+	}
+}
+
+func TestTriangle_Normal(t *testing.T) {
+	vertices := [3]vectors.Vector{{X: 0.0, Y: 0.0, Z: 0.0}, {X: 1.0, Y: 0.0, Z: 0.0}, {X: 0.0, Y: 0.0, Z: 1.0}}
+	flat := Triangle{Vertices: vertices}
+	smooth := Triangle{Vertices: vertices, VertexNormals: &[3]vectors.Vector{
+		{X: 0.0, Y: 1.0, Z: 0.0},
+		{X: 1.0, Y: 0.0, Z: 0.0},
+		{X: 0.0, Y: 1.0, Z: 0.0},
+	}}
+	tests := []struct {
+		name  string
+		tr    Triangle
+		point *vectors.Vector
+		want  *vectors.Vector
+	}{
+		{name: "Flat follows the winding", tr: flat, point: &vectors.Vector{X: 0.2, Y: 0.0, Z: 0.2}, want: &vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}},
+		{name: "Smooth at a vertex", tr: smooth, point: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}},
+		{name: "Smooth halfway along an edge", tr: smooth, point: &vectors.Vector{X: 0.5, Y: 0.0, Z: 0.0}, want: &vectors.Vector{X: 0.70710678, Y: 0.70710678, Z: 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tr.Normal(tt.point); !got.CloseTo(tt.want) {
+				t.Errorf("Triangle.Normal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriangle_UV(t *testing.T) {
+	tr := Triangle{
+		Vertices:  [3]vectors.Vector{{X: 0.0, Y: 0.0, Z: 0.0}, {X: 2.0, Y: 0.0, Z: 0.0}, {X: 0.0, Y: 2.0, Z: 0.0}},
+		VertexUVs: &[3]UV{{U: 0.0, V: 0.0}, {U: 1.0, V: 0.0}, {U: 0.0, V: 1.0}},
+	}
+	got := tr.UV(&vectors.Vector{X: 0.5, Y: 1.0, Z: 0.0})
+	if !utils.Close_enough(got.U, 0.25) || !utils.Close_enough(got.V, 0.5) {
+		t.Errorf("Triangle.UV() = %v, want {0.25 0.5}", got)
+	}
+}
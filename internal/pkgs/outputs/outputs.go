@@ -0,0 +1,65 @@
+package outputs
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+func SavePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = png.Encode(f, img)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// SaveCubemap writes one PNG per face, named like <prefix>_posx.png, in the
+// order of face_names.
+func SaveCubemap(dir string, prefix string, face_names [6]string, faces [6]image.Image) error {
+	for i, face := range faces {
+		path := filepath.Join(dir, fmt.Sprintf("%s_%s.png", prefix, face_names[i]))
+		if err := SavePNG(path, face); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnaglyphEye draws one eye of a red/cyan anaglyph into an image: red from
+// the left eye, green and blue from the right. Render both eyes into the
+// same image to make the anaglyph, without keeping a frame for each.
+type AnaglyphEye struct {
+	*image.RGBA
+	Left bool
+}
+
+func (e AnaglyphEye) Set(x int, y int, c color.Color) {
+	e.SetRGBA(x, y, color.RGBAModel.Convert(c).(color.RGBA))
+}
+
+func (e AnaglyphEye) SetRGBA(x int, y int, c color.RGBA) {
+	// Only this eye's channels, keeping the other eye's.
+	if !(image.Point{X: x, Y: y}.In(e.Rect)) {
+		return
+	}
+	colour := e.RGBAAt(x, y)
+	if e.Left {
+		colour.R = c.R
+	} else {
+		colour.G, colour.B = c.G, c.B
+	}
+	if c.A > colour.A {
+		colour.A = c.A
+	}
+	e.RGBA.SetRGBA(x, y, colour)
+}
@@ -0,0 +1,65 @@
+package outputs
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveCubemap(t *testing.T) {
+	dir := t.TempDir()
+	names := [6]string{"posx", "negx", "posy", "negy", "posz", "negz"}
+	var faces [6]image.Image
+	for i := range faces {
+		face := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		face.SetRGBA(0, 0, color.RGBA{uint8(i), 0, 0, 0xff})
+		faces[i] = face
+	}
+	if err := SaveCubemap(dir, "env", names, faces); err != nil {
+		t.Fatalf("SaveCubemap() error = %v", err)
+	}
+	for i, name := range names {
+		f, err := os.Open(filepath.Join(dir, "env_"+name+".png"))
+		if err != nil {
+			t.Fatalf("Missing cube map face: %v", err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("Can't decode cube map face: %v", err)
+		}
+		if r, _, _, _ := img.At(0, 0).RGBA(); r>>8 != uint32(i) {
+			t.Errorf("Face %s has red %d, want %d", name, r>>8, i)
+		}
+	}
+}
+
+func TestAnaglyphEye(t *testing.T) {
+	// Either eye can go first
+	left := []color.RGBA{{200, 10, 20, 0xff}, {0, 0, 0, 0}}
+	right := []color.RGBA{{30, 150, 160, 0xff}, {40, 50, 60, 0xff}}
+	want := []color.RGBA{{200, 150, 160, 0xff}, {0, 50, 60, 0xff}}
+	for _, left_first := range []bool{true, false} {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+		for _, is_left := range []bool{left_first, !left_first} {
+			eye := AnaglyphEye{RGBA: img, Left: is_left}
+			colours := right
+			if is_left {
+				colours = left
+			}
+			for x, colour := range colours {
+				eye.Set(x, 0, colour)
+			}
+			// Outside the image, which is ignored
+			eye.Set(2, 0, color.RGBA{0xff, 0xff, 0xff, 0xff})
+		}
+		for x := range want {
+			if got := img.RGBAAt(x, 0); got != want[x] {
+				t.Errorf("Anaglyph pixel %d = %v, want %v, left eye first %v", x, got, want[x], left_first)
+			}
+		}
+	}
+}
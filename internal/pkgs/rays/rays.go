@@ -6,20 +6,9 @@ import (
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
 )
 
-func FireRays(screen_vectors [][]*vectors.Vector, origin *vectors.Vector) [][]Ray {
-	var screen_rays [][]Ray
-	for _, row := range screen_vectors {
-		var screen_row []Ray
-		for _, screen_vector := range row {
-			screen_row = append(screen_row, MakeRay(
-				origin, screen_vector.Subtract(origin),
-			))
-		}
-		screen_rays = append(screen_rays, screen_row)
-	}
-
-	return screen_rays
-}
+// Generator gives the ray for sample number sample of the pixel in column x,
+// row y, so rays can be made as they are needed rather than all up front.
+type Generator func(x int, y int, sample int) Ray
 
 type Ray struct {
 	Origin    *vectors.Vector
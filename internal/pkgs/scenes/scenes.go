@@ -1,7 +1,9 @@
 package scenes
 
 import (
+	"image"
 	"image/color"
+	"image/draw"
 	"math"
 
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/lights"
@@ -18,6 +20,29 @@ type Scene struct {
 	Objects       []objects.Object
 	Lights        []lights.Light
 	AmbientColour color.RGBA
+	Accelerator   objects.AcceleratorKind // What Accelerated sorts the objects into, a BVH by default
+	MaxDepth      int                     // How many reflections to follow, 0 for none
+	Background    color.RGBA              // Seen by rays which miss everything
+
+	aggregate *objects.Aggregate // Set by Accelerated
+}
+
+func (s Scene) Accelerated() Scene {
+	// The scene with its objects sorted into an accelerator, so rays don't
+	// have to be tested against all of them. Render does this itself; call it before
+	// tracing rays one at a time, and again after changing Objects.
+	s.aggregate = objects.MakeAggregateUsing(s.Objects, s.Accelerator)
+	return s
+}
+
+func (s Scene) AcceleratedCached(cache objects.AcceleratorCache) (Scene, error) {
+	// As Accelerated, but the accelerator comes from cache when the objects
+	// haven't moved since it was built, and is saved there otherwise. The
+	// scene is always ready to render; the error only says the accelerator
+	// couldn't be saved.
+	var err error
+	s.aggregate, err = objects.MakeAggregateCached(s.Objects, s.Accelerator, cache)
+	return s, err
 }
 
 func (s Scene) ObjectsOtherThan(avoid_obj objects.Object) []objects.Object {
@@ -32,50 +57,245 @@ func (s Scene) ObjectsOtherThan(avoid_obj objects.Object) []objects.Object {
 	return return_objects
 }
 
-func (s Scene) Render(ray_matrix [][]rays.Ray) (colour_matrix [][]color.RGBA) {
-	// Given a matrix of rays, return a matrix of colour values.
-	for _, ray_row := range ray_matrix {
-		var colour_row []color.RGBA
-		for _, ray := range ray_row {
-			var colour color.RGBA
-			closest_obj, dist := s.ClosestObject(ray)
-			if closest_obj != nil {
-				surface_vector := ray.Origin.Add(ray.Direction.MultiplyScalar(dist))
-				mat := closest_obj.GetMaterial()
-				colour = ComputePhong(
-					mat,
-					s.Lights,
-					s.Objects,
-					s.AmbientColour,
-					surface_vector,
-					closest_obj.Normal(surface_vector),
-					ray.Direction.MultiplyScalar(-1),
-				)
+func (s Scene) Render(img draw.Image, samples int, generate rays.Generator) {
+	// Set every pixel of img to the average over samples rays for it. Rays
+	// are only generated as they are traced, and pixels go straight into img,
+	// so nothing else the size of the frame is kept.
+	s.RenderRegion(img, img.Bounds(), samples, generate)
+}
+
+func (s Scene) RenderRegion(img draw.Image, region image.Rectangle, samples int, generate rays.Generator) {
+	// Like Render, but only for the pixels of img inside region. generate is
+	// given each pixel's coordinates in img, so an image covering just part
+	// of the frame, e.g. a crop, gets the same rays as a full render there.
+	if s.aggregate == nil {
+		s = s.Accelerated()
+	}
+	// Only this render uses these, so they can remember shadows between pixels
+	shadows := s.lightOccluders()
+	// Saves converting every pixel to a color.Color, for images that can
+	rgba_img, has_rgba := img.(interface{ SetRGBA(x, y int, c color.RGBA) })
+	region = region.Intersect(img.Bounds())
+	sample_colours := make([]color.RGBA, samples)
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			for k := range sample_colours {
+				sample_colours[k] = s.trace(generate(x, y, k), 0.0, s.MaxDepth, shadows)
+			}
+			if has_rgba {
+				rgba_img.SetRGBA(x, y, averageColours(sample_colours))
+			} else {
+				img.Set(x, y, averageColours(sample_colours))
 			}
-			colour_row = append(colour_row, colour)
 		}
-		colour_matrix = append(colour_matrix, colour_row)
 	}
-	return
+}
+
+func (s Scene) Trace(ray rays.Ray) color.RGBA {
+	// The colour seen along a single ray. Unlike Render, nothing is
+	// remembered about shadows from one call to the next.
+	return s.trace(ray, 0.0, s.MaxDepth, s.lightOccluders())
+}
+
+func (s Scene) trace(ray rays.Ray, t_min float64, depth int, shadows []occluder) color.RGBA {
+	// Following reflections off anything less than fully matte, and rays
+	// through and off anything transparent, until depth runs out (Whitted).
+	hit, ok := s.closestHit(ray, t_min)
+	if !ok {
+		return s.Background
+	}
+	colour := computePhong(
+		hit.Material,
+		s.Lights,
+		shadows,
+		s.AmbientColour,
+		&hit.Point,
+		&hit.ShadingNormal,
+		ray.Direction.MultiplyScalar(-1),
+	)
+	if depth > 0 && hit.Material.Matte < 1.0 && hit.Material.Transparency <= 0.0 {
+		// What hit.Object.Reflect gives, without finding the normal again.
+		// Transparent surfaces reflect by Fresnel in transmitted instead.
+		// Starting just off the surface, so it doesn't reflect itself.
+		reflected_ray := objects.ComputeReflectedRay(ray, &hit.Point, &hit.ShadingNormal)
+		reflected := s.trace(reflected_ray, dist_threshold, depth-1, shadows)
+		colour = blendColours(colour, reflected, 1.0-hit.Material.Matte)
+	}
+	if depth > 0 && hit.Material.Transparency > 0.0 {
+		colour = blendColours(colour, s.transmitted(ray, hit, depth, shadows), hit.Material.Transparency)
+	}
+	return colour
+}
+
+func (s Scene) transmitted(ray rays.Ray, hit objects.Hit, depth int, shadows []occluder) color.RGBA {
+	// What's seen through a transparent surface: the refracted ray, and what
+	// the surface reflects, weighted by Fresnel. Surfaces are taken to be
+	// between the material and air, so rays leaving from inside bend the
+	// other way, and may not get out at all.
+	n1, n2 := 1.0, hit.Material.Refractive_index
+	normal := hit.ShadingNormal
+	if !hit.FrontFace {
+		n1, n2 = n2, n1
+		normal = *normal.MultiplyScalar(-1.0)
+	}
+	if n1 <= 0.0 || n2 <= 0.0 {
+		// Materials made without MakeMaterial
+		n1, n2 = 1.0, 1.0
+	}
+	direction := *ray.Direction
+	direction.Normalise()
+	// Shading normals can lean past the ray
+	cos_i := math.Max(0.0, -direction.Dot(&normal))
+
+	reflected_ray := objects.ComputeReflectedRay(rays.Ray{Origin: ray.Origin, Direction: &direction}, &hit.Point, &normal)
+	reflected := s.trace(reflected_ray, dist_threshold, depth-1, shadows)
+	refracted_direction, ok := direction.Refract(&normal, n1/n2)
+	if !ok {
+		// Total internal reflection
+		return reflected
+	}
+	refracted := s.trace(rays.Ray{Origin: &hit.Point, Direction: refracted_direction}, dist_threshold, depth-1, shadows)
+	return blendColours(refracted, reflected, schlickReflectance(cos_i, n1, n2))
+}
+
+func schlickReflectance(cos_i float64, n1 float64, n2 float64) float64 {
+	// How much light is reflected rather than refracted going from
+	// refractive index n1 to n2, cos_i from straight on, by Schlick's
+	// approximation to the Fresnel equations. Going into a lower index, the
+	// angle on the other side is the one that counts.
+	r0 := (n1 - n2) / (n1 + n2)
+	r0 *= r0
+	cos := cos_i
+	if n1 > n2 {
+		sin2_t := (n1 / n2) * (n1 / n2) * (1.0 - cos_i*cos_i)
+		if sin2_t > 1.0 {
+			return 1.0
+		}
+		cos = math.Sqrt(1.0 - sin2_t)
+	}
+	x := 1.0 - cos
+	return r0 + (1.0-r0)*x*x*x*x*x
+}
+
+func blendColours(a color.RGBA, b color.RGBA, b_weight float64) color.RGBA {
+	// Weighted between a and b, all b at 1.
+	blend := func(x uint8, y uint8) uint8 {
+		return clipFloat(math.Round((1.0-b_weight)*float64(x) + b_weight*float64(y)))
+	}
+	return color.RGBA{blend(a.R, b.R), blend(a.G, b.G), blend(a.B, b.B), blend(a.A, b.A)}
+}
+
+func averageColours(colours []color.RGBA) color.RGBA {
+	if len(colours) == 0 {
+		return color.RGBA{}
+	}
+	var totals [4]float64
+	for _, c := range colours {
+		totals[0] += float64(c.R)
+		totals[1] += float64(c.G)
+		totals[2] += float64(c.B)
+		totals[3] += float64(c.A)
+	}
+	n := float64(len(colours))
+	return color.RGBA{
+		clipFloat(math.Round(totals[0] / n)),
+		clipFloat(math.Round(totals[1] / n)),
+		clipFloat(math.Round(totals[2] / n)),
+		clipFloat(math.Round(totals[3] / n)),
+	}
 }
 
 func (s Scene) ClosestObject(ray rays.Ray) (objects.Object, float64) {
-	found_obj := false
-	var closest_dist float64
-	var closest_obj objects.Object
+	// The object hit first, from inside any groups, or nil.
+	hit, ok := s.ClosestHit(ray)
+	if !ok {
+		return nil, 0.0
+	}
+	return hit.Object, hit.T
+}
+
+func (s Scene) ClosestHit(ray rays.Ray) (objects.Hit, bool) {
+	return s.closestHit(ray, 0.0)
+}
+
+func (s Scene) closestHit(ray rays.Ray, t_min float64) (objects.Hit, bool) {
+	// Each object only needs to look nearer than the closest hit so far.
+	if s.aggregate != nil {
+		return s.aggregate.Intersect(ray, t_min, math.Inf(1))
+	}
+	var closest objects.Hit
+	found := false
+	t_max := math.Inf(1)
 	for _, obj := range s.Objects {
-		dists := obj.CollideDistances(ray)
-		for _, d := range dists {
-			if d > 0.0 {
-				if !found_obj || d < closest_dist {
-					closest_dist = d
-					closest_obj = obj
-					found_obj = true
-				}
+		if hit, ok := objects.Intersect(obj, ray, t_min, t_max); ok {
+			closest, t_max, found = hit, hit.T, true
+		}
+	}
+	return closest, found
+}
+
+func (s Scene) Occluded(ray rays.Ray, max_dist float64) bool {
+	// Whether anything is in the way of the ray before max_dist, e.g.
+	// between a surface and a light. Stops at the first thing found.
+	if s.aggregate != nil {
+		_, ok := s.aggregate.Occluded(ray, dist_threshold, max_dist)
+		return ok
+	}
+	return occludedBy(s.Objects)(ray, max_dist)
+}
+
+// Whether anything is in the way of a ray before max_dist
+type occluder func(ray rays.Ray, max_dist float64) bool
+
+func occludedBy(objs []objects.Object) occluder {
+	// Trying every object in turn.
+	return func(ray rays.Ray, max_dist float64) bool {
+		for _, obj := range objs {
+			if objects.Occluded(obj, ray, dist_threshold, max_dist) {
+				return true
 			}
 		}
+		return false
+	}
+}
+
+func (s Scene) cachedOccluder() occluder {
+	// Nearby points tend to be in the shadow of the same object, so the
+	// last one found is tried before anything else.
+	var last objects.Object
+	return func(ray rays.Ray, max_dist float64) bool {
+		if last != nil && objects.Occluded(last, ray, dist_threshold, max_dist) {
+			return true
+		}
+		blocker, ok := s.aggregate.Occluded(ray, dist_threshold, max_dist)
+		if ok {
+			last = blocker
+		}
+		return ok
 	}
-	return closest_obj, closest_dist
+}
+
+func (s Scene) lightOccluders() []occluder {
+	// What to check shadows from each light with. Once accelerated, each
+	// remembers what last cast a shadow from its light, so they're made
+	// afresh for each render rather than kept in the scene, where copies
+	// traced from other goroutines would share them.
+	if s.aggregate == nil {
+		return occludersFor(s.Lights, s.Occluded)
+	}
+	occluders := make([]occluder, len(s.Lights))
+	for i := range occluders {
+		occluders[i] = s.cachedOccluder()
+	}
+	return occluders
+}
+
+func occludersFor(lights []lights.Light, occluded occluder) []occluder {
+	occluders := make([]occluder, len(lights))
+	for i := range occluders {
+		occluders[i] = occluded
+	}
+	return occluders
 }
 
 func computeDiffuseSpecular(
@@ -86,7 +306,7 @@ func computeDiffuseSpecular(
 	surface_position *vectors.Vector,
 	surface_normal *vectors.Vector,
 	viewer_direction *vectors.Vector,
-	objects []objects.Object,
+	occluded occluder,
 ) ([3]float64, [3]float64) {
 	L := (light_position).Subtract(surface_position)
 	light_dist := L.Magnitude()
@@ -95,20 +315,7 @@ func computeDiffuseSpecular(
 
 	var diffuse [3]float64
 	var specular [3]float64
-	in_shadow := false
-	for _, obj := range objects {
-		obj_dists := obj.CollideDistances(L_ray)
-		for _, obj_dist := range obj_dists {
-			if obj_dist < light_dist && obj_dist > dist_threshold {
-				in_shadow = true
-				break
-			}
-		}
-		if in_shadow {
-			break
-		}
-	}
-	if !in_shadow {
+	if !occluded(L_ray, light_dist) {
 		R := L.MultiplyScalar(-1).Reflect(surface_normal)
 		R.Normalise()
 		diffuse_dot := L.Dot(surface_normal)
@@ -144,6 +351,12 @@ func clipFloat(float_val float64) uint8 {
 
 func ComputePhong(
 	m materials.Material, lights []lights.Light, objects []objects.Object, Ambient_color color.RGBA, surface_position *vectors.Vector, surface_normal *vectors.Vector, viewer_direction *vectors.Vector,
+) color.RGBA {
+	return computePhong(m, lights, occludersFor(lights, occludedBy(objects)), Ambient_color, surface_position, surface_normal, viewer_direction)
+}
+
+func computePhong(
+	m materials.Material, lights []lights.Light, occluders []occluder, Ambient_color color.RGBA, surface_position *vectors.Vector, surface_normal *vectors.Vector, viewer_direction *vectors.Vector,
 ) (illumination color.RGBA) {
 	illumination.A = 0xff
 
@@ -154,7 +367,7 @@ func ComputePhong(
 		m.Ambient_color.B = clipFloat(float64(Ambient_color.B) * m.Ambient_consts[2])
 	}
 	var light_totals [3]float64
-	for _, light := range lights {
+	for i, light := range lights {
 		diffuse, specular := computeDiffuseSpecular(
 			m.Diffuse_consts,
 			m.Specular_consts,
@@ -163,7 +376,7 @@ func ComputePhong(
 			surface_position,
 			surface_normal,
 			viewer_direction,
-			objects,
+			occluders[i],
 		)
 		light_totals[0] += float64(light.Color.R) * (diffuse[0] + specular[0])
 		light_totals[1] += float64(light.Color.G) * (diffuse[1] + specular[1])
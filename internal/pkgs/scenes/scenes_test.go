@@ -1,13 +1,18 @@
 package scenes
 
 import (
+	"image"
 	"image/color"
+	"math/rand"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/lights"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
 )
 
@@ -33,10 +38,10 @@ func Test_computeDiffuseSpecular(t *testing.T) {
 				Diffuse_const:    [3]float64{1.0, 1.0, 1.0},
 				Specular_const:   [3]float64{1.0, 1.0, 1.0},
 				alpha:            0.5,
-				light_position:   &vectors.Vector{0.0, 1.0, 0.0},
-				surface_position: &vectors.Vector{0.0, 0.0, 0.0},
-				surface_normal:   &vectors.Vector{0.0, 1.0, 0.0},
-				viewer_direction: &vectors.Vector{0.0, 1.0, 0.0},
+				light_position:   &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+				surface_position: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+				surface_normal:   &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+				viewer_direction: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
 			},
 			want:  [3]float64{1.0, 1.0, 1.0},
 			want1: [3]float64{1.0, 1.0, 1.0},
@@ -45,7 +50,7 @@ func Test_computeDiffuseSpecular(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var objs []objects.Object
-			got, got1 := computeDiffuseSpecular(tt.args.Diffuse_const, tt.args.Specular_const, tt.args.alpha, tt.args.light_position, tt.args.surface_position, tt.args.surface_normal, tt.args.viewer_direction, objs)
+			got, got1 := computeDiffuseSpecular(tt.args.Diffuse_const, tt.args.Specular_const, tt.args.alpha, tt.args.light_position, tt.args.surface_position, tt.args.surface_normal, tt.args.viewer_direction, occludedBy(objs))
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("computeDiffuseSpecular() got = %v, want %v", got, tt.want)
 			}
@@ -90,6 +95,116 @@ func Test_clipFloat(t *testing.T) {
 	}
 }
 
+func TestScene_Render(t *testing.T) {
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	s := Scene{
+		Objects: []objects.Object{objects.Sphere{
+			Radius:   1.0,
+			Center:   vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0},
+			Material: materials.MakeMaterial(white, 0.0, 0.0, 1.0, 1.0, 1.0),
+		}},
+		AmbientColour: white,
+	}
+	// The left column looks at the sphere, the right column looks away, and
+	// in the bottom row only every other sample looks at the sphere.
+	generate := func(x int, y int, sample int) rays.Ray {
+		direction := &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+		if x == 1 || (y == 1 && sample%2 == 1) {
+			direction = &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}
+		}
+		return rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, direction)
+	}
+	got := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	s.Render(got, 4, generate)
+	hit := s.Trace(generate(0, 0, 0))
+	want := [][]color.RGBA{
+		{hit, {}},
+		{averageColours([]color.RGBA{hit, {}}), {}},
+	}
+	for y, row := range want {
+		for x, colour := range row {
+			if got.RGBAAt(x, y) != colour {
+				t.Errorf("Scene.Render() pixel (%d, %d) = %v, want %v", x, y, got.RGBAAt(x, y), colour)
+			}
+		}
+	}
+}
+
+func TestScene_RenderRegion(t *testing.T) {
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	s := Scene{
+		Objects: []objects.Object{objects.Sphere{
+			Radius:   1.0,
+			Center:   vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0},
+			Material: materials.MakeMaterial(white, 0.0, 0.0, 1.0, 1.0, 1.0),
+		}},
+		Lights:        []lights.Light{{Color: white, Intensity: 1.0, Position: vectors.Vector{X: 0.0, Y: 5.0, Z: 0.0}}},
+		AmbientColour: white,
+	}
+	// A little 8x8 perspective view of the sphere
+	generate := func(x int, y int, sample int) rays.Ray {
+		direction := &vectors.Vector{X: (float64(x) - 3.5) / 10.0, Y: (3.5 - float64(y)) / 10.0, Z: 1.0}
+		return rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, direction)
+	}
+	full := renderImage(s, 8, 8, generate)
+	region := image.Rect(2, 3, 7, 5)
+
+	// Into an image of just the region, as for a crop, and into part of a
+	// full frame canvas, leaving the rest alone
+	crop := image.NewRGBA(region)
+	s.Render(crop, 1, generate)
+	canvas := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	s.RenderRegion(canvas, region, 1, generate)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			want := color.RGBA{}
+			if image.Pt(x, y).In(region) {
+				want = full.RGBAAt(x, y)
+				if got := crop.RGBAAt(x, y); got != want {
+					t.Errorf("Scene.Render() cropped pixel (%d, %d) = %v, want %v", x, y, got, want)
+				}
+			}
+			if got := canvas.RGBAAt(x, y); got != want {
+				t.Errorf("Scene.RenderRegion() pixel (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func Test_averageColours(t *testing.T) {
+	type args struct {
+		colours []color.RGBA
+	}
+	tests := []struct {
+		name string
+		args args
+		want color.RGBA
+	}{
+		{
+			name: "Single colour",
+			args: args{colours: []color.RGBA{{10, 20, 30, 0xff}}},
+			want: color.RGBA{10, 20, 30, 0xff},
+		},
+		{
+			name: "Half hit, half miss",
+			args: args{colours: []color.RGBA{{200, 100, 0, 0xff}, {0, 0, 0, 0}}},
+			want: color.RGBA{100, 50, 0, 128},
+		},
+		{
+			name: "No samples",
+			args: args{colours: []color.RGBA{}},
+			want: color.RGBA{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := averageColours(tt.args.colours); got != tt.want {
+				t.Errorf("averageColours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_ComputePhong(t *testing.T) {
 	type fields struct {
 		Color           color.RGBA
@@ -123,11 +238,11 @@ func Test_ComputePhong(t *testing.T) {
 				Matte_const:     0.5,
 			},
 			args: args{
-				lights:           []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{0.0, 1.0, 0.0}}},
+				lights:           []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}}},
 				Ambient_color:    color.RGBA{0.0, 0.0, 0.0, 0.0},
-				surface_position: &vectors.Vector{0.0, 0.0, 0.0},
-				surface_normal:   &vectors.Vector{0.0, 1.0, 0.0},
-				viewer_direction: &vectors.Vector{0.0, 1.0, 0.0},
+				surface_position: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+				surface_normal:   &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+				viewer_direction: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
 			},
 			wantIllumination: color.RGBA{0xff, 0xff, 0xff, 0xff},
 		},
@@ -142,11 +257,11 @@ func Test_ComputePhong(t *testing.T) {
 				Matte_const:     0.5,
 			},
 			args: args{
-				lights:           []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{1.0, -1.0, 0.0}}},
+				lights:           []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{X: 1.0, Y: -1.0, Z: 0.0}}},
 				Ambient_color:    color.RGBA{0.0, 0.0, 0.0, 0.0},
-				surface_position: &vectors.Vector{0.0, 0.0, 0.0},
-				surface_normal:   &vectors.Vector{-1.0, -1.0, 0.0},
-				viewer_direction: &vectors.Vector{0.0, 1.0, 0.0},
+				surface_position: &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+				surface_normal:   &vectors.Vector{X: -1.0, Y: -1.0, Z: 0.0},
+				viewer_direction: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
 			},
 			wantIllumination: color.RGBA{0, 0, 0, 0xff},
 		},
@@ -161,11 +276,11 @@ func Test_ComputePhong(t *testing.T) {
 				Matte_const:     0.5,
 			},
 			args: args{
-				lights:           []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{0.0, -1.0, 0.0}}},
+				lights:           []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{X: 0.0, Y: -1.0, Z: 0.0}}},
 				Ambient_color:    color.RGBA{0.0, 0.0, 0.0, 0.0},
-				surface_position: &vectors.Vector{1.0, 0.0, 0.0},
-				surface_normal:   &vectors.Vector{1.0, 1.0, 0.0},
-				viewer_direction: &vectors.Vector{0.0, 1.0, 0.0},
+				surface_position: &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0},
+				surface_normal:   &vectors.Vector{X: 1.0, Y: 1.0, Z: 0.0},
+				viewer_direction: &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0},
 			},
 			wantIllumination: color.RGBA{0, 0, 0, 0xff},
 		},
@@ -187,3 +302,374 @@ func Test_ComputePhong(t *testing.T) {
 		})
 	}
 }
+
+func TestScene_ClosestObject(t *testing.T) {
+	red := materials.Material{Color: color.RGBA{0xff, 0, 0, 0xff}}
+	blue := materials.Material{Color: color.RGBA{0, 0, 0xff, 0xff}}
+	near := objects.Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Material: red}
+	far := objects.Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}, Material: red}
+	// A group of one sphere moved to z = 10, with a material override
+	group := objects.MakeGroup([]objects.Object{near}, vectors.MakeTranslation(&vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}), &blue)
+	s := Scene{Objects: []objects.Object{group, far}}
+	tests := []struct {
+		name         string
+		ray          rays.Ray
+		wantDist     float64
+		wantMaterial materials.Material
+		wantNormal   *vectors.Vector
+	}{
+		{
+			name:         "Plain object in front",
+			ray:          rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}),
+			wantDist:     4.0,
+			wantMaterial: red,
+			wantNormal:   &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0},
+		},
+		{
+			name:         "Inside a group",
+			ray:          rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 20.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}),
+			wantDist:     9.0,
+			wantMaterial: blue,
+			wantNormal:   &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, dist := s.ClosestObject(tt.ray)
+			if obj == nil || dist != tt.wantDist {
+				t.Fatalf("Scene.ClosestObject() = %v, %v, want a hit at %v", obj, dist, tt.wantDist)
+			}
+			if got := obj.GetMaterial(); got != tt.wantMaterial {
+				t.Errorf("Scene.ClosestObject() material = %v, want %v", got.Color, tt.wantMaterial.Color)
+			}
+			point := tt.ray.Origin.Add(tt.ray.Direction.MultiplyScalar(dist))
+			if got := obj.Normal(point); !got.CloseTo(tt.wantNormal) {
+				t.Errorf("Scene.ClosestObject() normal = %v, want %v", got, tt.wantNormal)
+			}
+		})
+	}
+}
+
+// A floor scattered with spheres, lit from above, seen through a simple
+// pinhole looking down at it
+func makeBenchmarkScene(n int) (Scene, rays.Generator) {
+	r := rand.New(rand.NewSource(1))
+	grey := materials.MakeMaterial(color.RGBA{200, 200, 200, 0xff}, 0.005, 0.001, 0.002, 50, 1.0)
+	objs := []objects.Object{objects.Plane{PlaneNormal: vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}, Point: vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, Material: grey}}
+	for i := 0; i < n; i++ {
+		objs = append(objs, objects.Sphere{
+			Radius:   0.5,
+			Center:   vectors.Vector{X: 100.0*r.Float64() - 50.0, Y: 0.5, Z: 100.0*r.Float64() + 10.0},
+			Material: grey,
+		})
+	}
+	s := Scene{
+		Objects:       objs,
+		Lights:        []lights.Light{{Color: color.RGBA{0xff, 0xff, 0xff, 0xff}, Intensity: 1.0, Position: vectors.Vector{X: 0.0, Y: 50.0, Z: 0.0}}},
+		AmbientColour: color.RGBA{100, 100, 100, 0xff},
+	}
+	generate := func(x int, y int, sample int) rays.Ray {
+		direction := &vectors.Vector{X: float64(x-32) / 32.0, Y: -0.5 - float64(y)/48.0, Z: 1.0}
+		return rays.MakeRay(&vectors.Vector{X: 0.0, Y: 20.0, Z: 0.0}, direction)
+	}
+	return s, generate
+}
+
+// The benchmark scene lit instead by n_lights low lights spread along the x
+// axis, so the spheres cast long shadows
+func makeLowLitBenchmarkScene(n int, n_lights int) (Scene, rays.Generator) {
+	s, generate := makeBenchmarkScene(n)
+	s.Lights = nil
+	for i := 0; i < n_lights; i++ {
+		x := 100.0*float64(i)/float64(n_lights) - 50.0
+		s.Lights = append(s.Lights, lights.Light{Color: color.RGBA{0x40, 0x40, 0x40, 0xff}, Intensity: 1.0, Position: vectors.Vector{X: x, Y: 5.0, Z: 0.0}})
+	}
+	return s, generate
+}
+
+func renderImage(s Scene, width int, height int, generate rays.Generator) *image.RGBA {
+	// One sample per pixel
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	s.Render(img, 1, generate)
+	return img
+}
+
+func BenchmarkScene_Render(b *testing.B) {
+	s, generate := makeBenchmarkScene(1000)
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for i := 0; i < b.N; i++ {
+		s.Render(img, 1, generate)
+	}
+}
+
+func BenchmarkScene_Render_unaccelerated(b *testing.B) {
+	// The same pixels as Render, testing every ray against every object
+	s, generate := makeBenchmarkScene(1000)
+	for i := 0; i < b.N; i++ {
+		for y := 0; y < 48; y++ {
+			for x := 0; x < 64; x++ {
+				s.Trace(generate(x, y, 0))
+			}
+		}
+	}
+}
+
+func TestScene_Accelerated(t *testing.T) {
+	// Tracing with any accelerator sees the same as testing every object
+	s, generate := makeLowLitBenchmarkScene(200, 4)
+	kinds := map[string]objects.AcceleratorKind{"BVH": objects.BVHAccelerator, "Grid": objects.GridAccelerator, "KDTree": objects.KDTreeAccelerator}
+	for name, kind := range kinds {
+		s.Accelerator = kind
+		accelerated := s.Accelerated()
+		for y := 0; y < 48; y++ {
+			for x := 0; x < 64; x++ {
+				if got, want := accelerated.Trace(generate(x, y, 0)), s.Trace(generate(x, y, 0)); got != want {
+					t.Fatalf("Scene.Trace() at %v, %v = %v with %v, want %v", x, y, got, name, want)
+				}
+			}
+		}
+	}
+}
+
+func TestScene_AcceleratedCached(t *testing.T) {
+	// A scene whose accelerator came from the cache renders the same as one
+	// which built its own
+	s, generate := makeLowLitBenchmarkScene(200, 1)
+	s.Accelerator = objects.KDTreeAccelerator
+	want := renderImage(s, 64, 48, generate)
+	cache := objects.AcceleratorCache{Dir: t.TempDir()}
+	for i := 0; i < 2; i++ {
+		cached, err := s.AcceleratedCached(cache)
+		if err != nil {
+			t.Fatalf("Scene.AcceleratedCached() error = %v", err)
+		}
+		if got := renderImage(cached, 64, 48, generate); !reflect.DeepEqual(got, want) {
+			t.Errorf("Scene.AcceleratedCached() renders differently on try %d", i+1)
+		}
+	}
+}
+
+func BenchmarkScene_Render_lights(b *testing.B) {
+	s, generate := makeLowLitBenchmarkScene(1000, 16)
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for i := 0; i < b.N; i++ {
+		s.Render(img, 1, generate)
+	}
+}
+
+func TestScene_Occluded(t *testing.T) {
+	near := objects.Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 5.0}}
+	far := objects.Sphere{Radius: 1.0, Center: vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0}}
+	s := Scene{Objects: []objects.Object{far, near}}
+	origin := &vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}
+	tests := []struct {
+		name     string
+		ray      rays.Ray
+		max_dist float64
+		want     bool
+	}{
+		{name: "Behind both spheres", ray: rays.MakeRay(origin, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), max_dist: 20.0, want: true},
+		{name: "Short of the near sphere", ray: rays.MakeRay(origin, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}), max_dist: 3.5, want: false},
+		{name: "Past the spheres", ray: rays.MakeRay(origin, &vectors.Vector{X: 1.0, Y: 0.0, Z: 1.0}), max_dist: 20.0, want: false},
+		{name: "Away from the spheres", ray: rays.MakeRay(origin, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}), max_dist: 20.0, want: false},
+		{name: "Leaving the near sphere's surface", ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 6.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}), max_dist: 20.0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Occluded(tt.ray, tt.max_dist); got != tt.want {
+				t.Errorf("Scene.Occluded() = %v, want %v", got, tt.want)
+			}
+			if got := s.Accelerated().Occluded(tt.ray, tt.max_dist); got != tt.want {
+				t.Errorf("Scene.Occluded() = %v accelerated, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScene_cachedOccluder(t *testing.T) {
+	// Remembering the last blocker mustn't change which rays are blocked,
+	// whether or not it's the one in the way this time
+	s, _ := makeLowLitBenchmarkScene(200, 1)
+	s = s.Accelerated()
+	occluded := s.lightOccluders()[0]
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		point := &vectors.Vector{X: 100.0*r.Float64() - 50.0, Y: 0.0, Z: 100.0*r.Float64() + 10.0}
+		to_light := s.Lights[0].Position.Subtract(point)
+		dist := to_light.Magnitude()
+		ray := rays.MakeRay(point, to_light)
+		if got, want := occluded(ray, dist), s.Occluded(ray, dist); got != want {
+			t.Fatalf("cached occluder = %v from %v, want %v", got, point, want)
+		}
+	}
+}
+
+func TestScene_RenderRegion_concurrent(t *testing.T) {
+	// Copies of one accelerated scene rendering at once share nothing they
+	// change, so see the same as a single render
+	s, generate := makeLowLitBenchmarkScene(200, 4)
+	s = s.Accelerated()
+	want := renderImage(s, 64, 48, generate)
+	got := image.NewRGBA(want.Rect)
+	var wg sync.WaitGroup
+	for _, half := range []image.Rectangle{image.Rect(0, 0, 64, 24), image.Rect(0, 24, 64, 48)} {
+		wg.Add(1)
+		go func(half image.Rectangle) {
+			defer wg.Done()
+			s.RenderRegion(got, half, 1, generate)
+		}(half)
+	}
+	wg.Wait()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scene.RenderRegion() in halves at once differs from a single render")
+	}
+}
+
+func TestScene_Trace_reflections(t *testing.T) {
+	// A mirror ahead, facing back at a wall behind the viewer. Without
+	// lights everything is its ambient colour, so looks the same from
+	// anywhere.
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	sky := color.RGBA{0, 0, 0x80, 0xff}
+	mirror := func(matte float64) objects.Object {
+		return objects.Plane{
+			PlaneNormal: vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0},
+			Point:       vectors.Vector{X: 0.0, Y: 0.0, Z: 10.0},
+			Material:    materials.MakeMaterial(color.RGBA{0xff, 0, 0, 0xff}, 0.0, 0.0, 1.0/255.0, 1.0, matte),
+		}
+	}
+	wall := objects.Plane{
+		PlaneNormal: vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		Point:       vectors.Vector{X: 0.0, Y: 0.0, Z: -10.0},
+		Material:    materials.MakeMaterial(color.RGBA{0, 0xff, 0, 0xff}, 0.0, 0.0, 1.0/255.0, 1.0, 1.0),
+	}
+	red, green := color.RGBA{0xff, 0, 0, 0xff}, color.RGBA{0, 0xff, 0, 0xff}
+	ahead := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0})
+	slanted := rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 1.0, Z: 1.0})
+	tests := []struct {
+		name    string
+		objects []objects.Object
+		depth   int
+		ray     rays.Ray
+		want    color.RGBA
+	}{
+		{name: "Mirror, no reflections", objects: []objects.Object{mirror(0.0), wall}, depth: 0, ray: ahead, want: red},
+		{name: "Mirror", objects: []objects.Object{mirror(0.0), wall}, depth: 1, ray: ahead, want: green},
+		{name: "Half matte", objects: []objects.Object{mirror(0.5), wall}, depth: 1, ray: ahead, want: color.RGBA{0x80, 0x80, 0, 0xff}},
+		{name: "Matte", objects: []objects.Object{mirror(1.0), wall}, depth: 1, ray: ahead, want: red},
+		{name: "Reflecting the sky", objects: []objects.Object{mirror(0.0)}, depth: 1, ray: ahead, want: sky},
+		{name: "Missing everything", objects: []objects.Object{mirror(0.0)}, depth: 1, ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 0.0, Y: 0.0, Z: -1.0}), want: sky},
+		// Between two mirrors the ray bounces until it runs out of depth
+		{name: "Facing mirrors", objects: []objects.Object{mirror(0.0), objects.Plane{PlaneNormal: wall.PlaneNormal, Point: wall.Point, Material: mirror(0.0).GetMaterial()}}, depth: 5, ray: slanted, want: red},
+		{name: "Mirror seen in the wall", objects: []objects.Object{mirror(0.0), objects.Plane{PlaneNormal: wall.PlaneNormal, Point: wall.Point, Material: materials.MakeMaterial(green, 0.0, 0.0, 1.0/255.0, 1.0, 0.0)}}, depth: 1, ray: slanted, want: green},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scene{Objects: tt.objects, AmbientColour: white, MaxDepth: tt.depth, Background: sky}
+			if got := s.Trace(tt.ray); got != tt.want {
+				t.Errorf("Scene.Trace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_blendColours(t *testing.T) {
+	a := color.RGBA{200, 100, 0, 0xff}
+	b := color.RGBA{0, 101, 0xff, 0}
+	tests := []struct {
+		name     string
+		b_weight float64
+		want     color.RGBA
+	}{
+		{name: "All a", b_weight: 0.0, want: a},
+		{name: "All b", b_weight: 1.0, want: b},
+		{name: "Halfway", b_weight: 0.5, want: color.RGBA{100, 101, 128, 128}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blendColours(a, b, tt.b_weight); got != tt.want {
+				t.Errorf("blendColours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_schlickReflectance(t *testing.T) {
+	tests := []struct {
+		name  string
+		cos_i float64
+		n1    float64
+		n2    float64
+		want  float64
+	}{
+		{name: "Straight into glass", cos_i: 1.0, n1: 1.0, n2: 1.5, want: 0.04},
+		{name: "Straight out of glass", cos_i: 1.0, n1: 1.5, n2: 1.0, want: 0.04},
+		{name: "Glancing", cos_i: 0.0, n1: 1.0, n2: 1.5, want: 1.0},
+		{name: "Same refractive index", cos_i: 1.0, n1: 1.33, n2: 1.33, want: 0.0},
+		{name: "Past the critical angle", cos_i: 0.5, n1: 1.5, n2: 1.0, want: 1.0},
+		{name: "Into water at 60 degrees", cos_i: 0.5, n1: 1.0, n2: 1.33, want: 0.0506345},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schlickReflectance(tt.cos_i, tt.n1, tt.n2); !utils.Close_enough(got, tt.want) {
+				t.Errorf("schlickReflectance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScene_Trace_refraction(t *testing.T) {
+	// A red glass ball in front of a green wall, lit only by ambient light so
+	// colours don't depend on the angle they're seen from.
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	sky := color.RGBA{0, 0, 0x80, 0xff}
+	red, green := color.RGBA{0xff, 0, 0, 0xff}, color.RGBA{0, 0xff, 0, 0xff}
+	shiny_ball := func(refractive_index float64, matte float64, transparency float64) objects.Object {
+		return objects.Sphere{
+			Radius:   1.0,
+			Center:   vectors.Vector{X: 0.0, Y: 0.0, Z: 0.0},
+			Material: materials.MakeMaterial(red, 0.0, 0.0, 1.0/255.0, 1.0, matte).WithTransparency(transparency, refractive_index),
+		}
+	}
+	ball := func(refractive_index float64) objects.Object {
+		return shiny_ball(refractive_index, 1.0, 1.0)
+	}
+	wall := objects.Plane{
+		PlaneNormal: vectors.Vector{X: -1.0, Y: 0.0, Z: 0.0},
+		Point:       vectors.Vector{X: 5.0, Y: 0.0, Z: 0.0},
+		Material:    materials.MakeMaterial(green, 0.0, 0.0, 1.0/255.0, 1.0, 1.0),
+	}
+	through_centre := rays.MakeRay(&vectors.Vector{X: -5.0, Y: 0.0, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0})
+	tests := []struct {
+		name  string
+		ball  objects.Object
+		depth int
+		ray   rays.Ray
+		want  color.RGBA
+	}{
+		{name: "Invisible ball", ball: ball(1.0), depth: 2, ray: through_centre, want: green},
+		{name: "Not deep enough to get out", ball: ball(1.0), depth: 1, ray: through_centre, want: red},
+		{name: "Opaque", ball: objects.Sphere{Radius: 1.0, Material: materials.MakeMaterial(red, 0.0, 0.0, 1.0/255.0, 1.0, 1.0)}, depth: 2, ray: through_centre, want: red},
+		// 4% reflected at each surface: the sky on the way in, and the red
+		// inside on the way out
+		{name: "Glass", ball: ball(1.5), depth: 2, ray: through_centre, want: color.RGBA{10, 235, 5, 0xff}},
+		// From inside, bent away from the normal on the way out, with 4% of
+		// the red inside reflected
+		{name: "Leaving the ball", ball: ball(1.5), depth: 1, ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.2, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}), want: color.RGBA{10, 245, 0, 0xff}},
+		// Only Fresnel reflects off transparent surfaces, however shiny: half
+		// red at each side of the ball, and none of the sky as a mirror would
+		{name: "Half transparent mirror", ball: shiny_ball(1.0, 0.0, 0.5), depth: 2, ray: through_centre, want: color.RGBA{192, 64, 0, 0xff}},
+		// With 4% reflected at each surface as for glass
+		{name: "Half transparent shiny glass", ball: shiny_ball(1.5, 0.5, 0.5), depth: 2, ray: through_centre, want: color.RGBA{192, 59, 3, 0xff}},
+		// Hitting the surface at 64 degrees, past the critical angle
+		{name: "Total internal reflection", ball: ball(1.5), depth: 1, ray: rays.MakeRay(&vectors.Vector{X: 0.0, Y: 0.9, Z: 0.0}, &vectors.Vector{X: 1.0, Y: 0.0, Z: 0.0}), want: red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scene{Objects: []objects.Object{tt.ball, wall}, AmbientColour: white, MaxDepth: tt.depth, Background: sky}
+			if got := s.Trace(tt.ray); got != tt.want {
+				t.Errorf("Scene.Trace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package vectors
+
+import "math"
+
+// A 4x4 matrix, indexed [row][column], for transforming points and
+// directions. Vectors go on the right as columns, so in m.Multiply(n) n
+// happens first.
+type Matrix [4][4]float64
+
+func Identity() *Matrix {
+	return &Matrix{
+		{1.0, 0.0, 0.0, 0.0},
+		{0.0, 1.0, 0.0, 0.0},
+		{0.0, 0.0, 1.0, 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	}
+}
+
+func MakeTranslation(offset *Vector) *Matrix {
+	m := Identity()
+	m[0][3] = offset.X
+	m[1][3] = offset.Y
+	m[2][3] = offset.Z
+	return m
+}
+
+func MakeScaling(x float64, y float64, z float64) *Matrix {
+	m := Identity()
+	m[0][0] = x
+	m[1][1] = y
+	m[2][2] = z
+	return m
+}
+
+func MakeRotation(axis *Vector, degrees float64) *Matrix {
+	// Rotation about an axis through the origin. Positive angles turn y
+	// towards z about x, z towards x about y, and x towards y about z.
+	return MakeQuaternion(axis, degrees).Matrix()
+}
+
+func (m *Matrix) Multiply(n *Matrix) *Matrix {
+	var product Matrix
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				product[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return &product
+}
+
+func (m *Matrix) Transpose() *Matrix {
+	var transposed Matrix
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			transposed[i][j] = m[j][i]
+		}
+	}
+	return &transposed
+}
+
+func (m *Matrix) Inverse() (*Matrix, bool) {
+	// Gauss-Jordan elimination with partial pivoting. Returns false if m
+	// can't be inverted, e.g. a scaling by zero.
+	a := *m
+	inverse := *Identity()
+	for column := 0; column < 4; column++ {
+		pivot := column
+		for row := column + 1; row < 4; row++ {
+			if math.Abs(a[row][column]) > math.Abs(a[pivot][column]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][column]) < 1e-12 {
+			return nil, false
+		}
+		a[column], a[pivot] = a[pivot], a[column]
+		inverse[column], inverse[pivot] = inverse[pivot], inverse[column]
+
+		scale := 1.0 / a[column][column]
+		for j := 0; j < 4; j++ {
+			a[column][j] *= scale
+			inverse[column][j] *= scale
+		}
+		for row := 0; row < 4; row++ {
+			if row == column {
+				continue
+			}
+			factor := a[row][column]
+			for j := 0; j < 4; j++ {
+				a[row][j] -= factor * a[column][j]
+				inverse[row][j] -= factor * inverse[column][j]
+			}
+		}
+	}
+	return &inverse, true
+}
+
+func (m *Matrix) TransformPoint(v *Vector) *Vector {
+	// Points are moved by translations.
+	return &Vector{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z + m[0][3],
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z + m[1][3],
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z + m[2][3],
+	}
+}
+
+func (m *Matrix) TransformDirection(v *Vector) *Vector {
+	// Directions aren't moved by translations.
+	return &Vector{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
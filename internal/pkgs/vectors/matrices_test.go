@@ -0,0 +1,88 @@
+package vectors
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+)
+
+func matricesClose(m *Matrix, n *Matrix) bool {
+	for i := 0; i < 4; i++ {
+		if !utils.Slice_close_enough(m[i][:], n[i][:]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatrix_TransformPoint(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix
+		v    *Vector
+		want *Vector
+	}{
+		{name: "Identity", m: Identity(), v: &Vector{1.0, 2.0, 3.0}, want: &Vector{1.0, 2.0, 3.0}},
+		{name: "Translation", m: MakeTranslation(&Vector{1.0, -1.0, 2.0}), v: &Vector{1.0, 2.0, 3.0}, want: &Vector{2.0, 1.0, 5.0}},
+		{name: "Scaling", m: MakeScaling(2.0, 3.0, -1.0), v: &Vector{1.0, 2.0, 3.0}, want: &Vector{2.0, 6.0, -3.0}},
+		{name: "Rotation about x", m: MakeRotation(&Vector{1.0, 0.0, 0.0}, 90.0), v: &Vector{0.0, 1.0, 0.0}, want: &Vector{0.0, 0.0, 1.0}},
+		{name: "Rotation about y", m: MakeRotation(&Vector{0.0, 2.0, 0.0}, 90.0), v: &Vector{0.0, 0.0, 1.0}, want: &Vector{1.0, 0.0, 0.0}},
+		{name: "Rotation about z", m: MakeRotation(&Vector{0.0, 0.0, 1.0}, 90.0), v: &Vector{1.0, 0.0, 0.0}, want: &Vector{0.0, 1.0, 0.0}},
+		{
+			name: "Scale, then move",
+			m:    MakeTranslation(&Vector{0.0, 0.0, 10.0}).Multiply(MakeScaling(2.0, 2.0, 2.0)),
+			v:    &Vector{1.0, 1.0, 1.0},
+			want: &Vector{2.0, 2.0, 12.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.TransformPoint(tt.v); !got.CloseTo(tt.want) {
+				t.Errorf("Matrix.TransformPoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrix_TransformDirection(t *testing.T) {
+	m := MakeTranslation(&Vector{5.0, 5.0, 5.0}).Multiply(MakeScaling(2.0, 1.0, 1.0))
+	if got := m.TransformDirection(&Vector{1.0, 1.0, 0.0}); !got.CloseTo(&Vector{2.0, 1.0, 0.0}) {
+		t.Errorf("Matrix.TransformDirection() = %v, want {2 1 0}", got)
+	}
+}
+
+func TestMatrix_Inverse(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      *Matrix
+		wantOk bool
+	}{
+		{name: "Identity", m: Identity(), wantOk: true},
+		{
+			name:   "Rotate, scale and move",
+			m:      MakeTranslation(&Vector{1.0, 2.0, 3.0}).Multiply(MakeRotation(&Vector{1.0, 1.0, 0.0}, 30.0)).Multiply(MakeScaling(2.0, 0.5, 3.0)),
+			wantOk: true,
+		},
+		{name: "Needs pivoting", m: &Matrix{{0.0, 1.0, 0.0, 0.0}, {1.0, 0.0, 0.0, 0.0}, {0.0, 0.0, 1.0, 0.0}, {0.0, 0.0, 0.0, 1.0}}, wantOk: true},
+		{name: "Flattened", m: MakeScaling(1.0, 0.0, 1.0), wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inverse, ok := tt.m.Inverse()
+			if ok != tt.wantOk {
+				t.Fatalf("Matrix.Inverse() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && !matricesClose(tt.m.Multiply(inverse), Identity()) {
+				t.Errorf("Matrix.Inverse() = %v, which doesn't undo %v", inverse, tt.m)
+			}
+		})
+	}
+}
+
+func TestMatrix_Transpose(t *testing.T) {
+	m := MakeTranslation(&Vector{1.0, 2.0, 3.0})
+	want := &Matrix{{1.0, 0.0, 0.0, 0.0}, {0.0, 1.0, 0.0, 0.0}, {0.0, 0.0, 1.0, 0.0}, {1.0, 2.0, 3.0, 1.0}}
+	if got := m.Transpose(); !matricesClose(got, want) {
+		t.Errorf("Matrix.Transpose() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package vectors
+
+import "math"
+
+// A quaternion W + Xi + Yj + Zk. Unit quaternions are rotations.
+type Quaternion struct {
+	W float64
+	X float64
+	Y float64
+	Z float64
+}
+
+func MakeQuaternion(axis *Vector, degrees float64) *Quaternion {
+	// The rotation by degrees about axis, which needn't be normalised.
+	unit := *axis
+	unit.Normalise()
+	half := degrees * math.Pi / 360.0
+	sin := math.Sin(half)
+	return &Quaternion{W: math.Cos(half), X: unit.X * sin, Y: unit.Y * sin, Z: unit.Z * sin}
+}
+
+func (q *Quaternion) Magnitude() float64 {
+	return math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+}
+
+func (q *Quaternion) Normalise() {
+	m := q.Magnitude()
+	if m != 0.0 {
+		q.W = q.W / m
+		q.X = q.X / m
+		q.Y = q.Y / m
+		q.Z = q.Z / m
+	}
+}
+
+func (q *Quaternion) Multiply(r *Quaternion) *Quaternion {
+	// As rotations, r happens first.
+	return &Quaternion{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+func (q *Quaternion) Conjugate() *Quaternion {
+	// The opposite rotation, for unit quaternions.
+	return &Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+func (q *Quaternion) Rotate(v *Vector) *Vector {
+	rotated := q.Multiply(&Quaternion{X: v.X, Y: v.Y, Z: v.Z}).Multiply(q.Conjugate())
+	return &Vector{X: rotated.X, Y: rotated.Y, Z: rotated.Z}
+}
+
+func (q *Quaternion) Slerp(r *Quaternion, t float64) *Quaternion {
+	// Part way between two rotations, turning at a steady rate and the
+	// short way round.
+	dot := q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+	end := *r
+	if dot < 0.0 {
+		dot = -dot
+		end = Quaternion{W: -r.W, X: -r.X, Y: -r.Y, Z: -r.Z}
+	}
+	a, b := 1.0-t, t
+	if dot < 0.9995 {
+		// Otherwise they're so close that a straight line is fine
+		angle := math.Acos(dot)
+		a = math.Sin((1.0-t)*angle) / math.Sin(angle)
+		b = math.Sin(t*angle) / math.Sin(angle)
+	}
+	result := &Quaternion{
+		W: a*q.W + b*end.W,
+		X: a*q.X + b*end.X,
+		Y: a*q.Y + b*end.Y,
+		Z: a*q.Z + b*end.Z,
+	}
+	result.Normalise()
+	return result
+}
+
+func (q *Quaternion) Matrix() *Matrix {
+	// The same rotation as a matrix, for a unit quaternion.
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return &Matrix{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y), 0.0},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x), 0.0},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y), 0.0},
+		{0.0, 0.0, 0.0, 1.0},
+	}
+}
@@ -0,0 +1,62 @@
+package vectors
+
+import (
+	"testing"
+
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/utils"
+)
+
+func TestQuaternion_Rotate(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Quaternion
+		v    *Vector
+		want *Vector
+	}{
+		{name: "No rotation", q: MakeQuaternion(&Vector{0.0, 1.0, 0.0}, 0.0), v: &Vector{1.0, 2.0, 3.0}, want: &Vector{1.0, 2.0, 3.0}},
+		{name: "Quarter turn about z", q: MakeQuaternion(&Vector{0.0, 0.0, 1.0}, 90.0), v: &Vector{1.0, 0.0, 0.0}, want: &Vector{0.0, 1.0, 0.0}},
+		{name: "Half turn about y", q: MakeQuaternion(&Vector{0.0, 1.0, 0.0}, 180.0), v: &Vector{1.0, 1.0, 0.0}, want: &Vector{-1.0, 1.0, 0.0}},
+		{name: "Third turn about the diagonal", q: MakeQuaternion(&Vector{1.0, 1.0, 1.0}, 120.0), v: &Vector{1.0, 0.0, 0.0}, want: &Vector{0.0, 1.0, 0.0}},
+		{
+			name: "Two quarter turns",
+			q:    MakeQuaternion(&Vector{1.0, 0.0, 0.0}, 90.0).Multiply(MakeQuaternion(&Vector{0.0, 0.0, 1.0}, 90.0)),
+			v:    &Vector{1.0, 0.0, 0.0},
+			want: &Vector{0.0, 0.0, 1.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Rotate(tt.v); !got.CloseTo(tt.want) {
+				t.Errorf("Quaternion.Rotate() = %v, want %v", got, tt.want)
+			}
+			if got := tt.q.Matrix().TransformDirection(tt.v); !got.CloseTo(tt.want) {
+				t.Errorf("Quaternion.Matrix() rotates to %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuaternion_Slerp(t *testing.T) {
+	start := MakeQuaternion(&Vector{0.0, 0.0, 1.0}, 0.0)
+	end := MakeQuaternion(&Vector{0.0, 0.0, 1.0}, 90.0)
+	tests := []struct {
+		name string
+		t    float64
+		want *Vector
+	}{
+		{name: "Start", t: 0.0, want: &Vector{1.0, 0.0, 0.0}},
+		{name: "Half way", t: 0.5, want: &Vector{0.70710678, 0.70710678, 0.0}},
+		{name: "End", t: 1.0, want: &Vector{0.0, 1.0, 0.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := start.Slerp(end, tt.t)
+			if got := q.Rotate(&Vector{1.0, 0.0, 0.0}); !got.CloseTo(tt.want) {
+				t.Errorf("Quaternion.Slerp() rotates to %v, want %v", got, tt.want)
+			}
+			if !utils.Close_enough(q.Magnitude(), 1.0) {
+				t.Errorf("Quaternion.Slerp() magnitude = %v, want 1", q.Magnitude())
+			}
+		})
+	}
+}
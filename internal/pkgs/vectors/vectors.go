@@ -50,3 +50,26 @@ func (v *Vector) Reflect(surface_normal *Vector) (reflected_vector *Vector) {
 	reflected_vector = v.Subtract(surface_normal.MultiplyScalar(2 * v.Dot(surface_normal)))
 	return
 }
+
+func (v *Vector) Refract(surface_normal *Vector, eta float64) (refracted_vector *Vector, ok bool) {
+	// Bent by Snell's law passing through a surface, where eta is the
+	// refractive index on v's side over that on the other. v and the normal
+	// should be unit vectors, facing against each other. Not ok for total
+	// internal reflection, when nothing gets through.
+	cos_i := -v.Dot(surface_normal)
+	sin2_t := eta * eta * (1.0 - cos_i*cos_i)
+	if sin2_t > 1.0 {
+		return nil, false
+	}
+	cos_t := math.Sqrt(1.0 - sin2_t)
+	refracted_vector = v.MultiplyScalar(eta).Add(surface_normal.MultiplyScalar(eta*cos_i - cos_t))
+	return refracted_vector, true
+}
+
+func (v *Vector) Cross(u *Vector) *Vector {
+	return &Vector{
+		X: v.Y*u.Z - v.Z*u.Y,
+		Y: v.Z*u.X - v.X*u.Z,
+		Z: v.X*u.Y - v.Y*u.X,
+	}
+}
@@ -163,3 +163,116 @@ func TestVector_Reflect(t *testing.T) {
 		})
 	}
 }
+
+func TestVector_Refract(t *testing.T) {
+	diagonal := &Vector{X: 1.0, Y: -1.0, Z: 0.0}
+	diagonal.Normalise()
+	type args struct {
+		surface_normal *Vector
+		eta            float64
+	}
+	tests := []struct {
+		name                 string
+		v                    *Vector
+		args                 args
+		wantRefracted_vector *Vector
+		wantOk               bool
+	}{
+		{
+			name:                 "Straight through",
+			v:                    &Vector{X: 0.0, Y: -1.0, Z: 0.0},
+			args:                 args{surface_normal: &Vector{X: 0.0, Y: 1.0, Z: 0.0}, eta: 1.0 / 1.5},
+			wantRefracted_vector: &Vector{X: 0.0, Y: -1.0, Z: 0.0},
+			wantOk:               true,
+		},
+		{
+			name:                 "Same refractive index",
+			v:                    diagonal,
+			args:                 args{surface_normal: &Vector{X: 0.0, Y: 1.0, Z: 0.0}, eta: 1.0},
+			wantRefracted_vector: diagonal,
+			wantOk:               true,
+		},
+		{
+			// sin 45 / 1.5 = 0.4714
+			name:                 "Into glass",
+			v:                    diagonal,
+			args:                 args{surface_normal: &Vector{X: 0.0, Y: 1.0, Z: 0.0}, eta: 1.0 / 1.5},
+			wantRefracted_vector: &Vector{X: 0.47140452, Y: -0.88191710, Z: 0.0},
+			wantOk:               true,
+		},
+		{
+			// sin 45 * 1.2 = 0.8485
+			name:                 "Out of water",
+			v:                    diagonal,
+			args:                 args{surface_normal: &Vector{X: 0.0, Y: 1.0, Z: 0.0}, eta: 1.2},
+			wantRefracted_vector: &Vector{X: 0.84852814, Y: -0.52915026, Z: 0.0},
+			wantOk:               true,
+		},
+		{
+			// Past the critical angle, 41.8 degrees
+			name:   "Total internal reflection",
+			v:      diagonal,
+			args:   args{surface_normal: &Vector{X: 0.0, Y: 1.0, Z: 0.0}, eta: 1.5},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRefracted_vector, gotOk := tt.v.Refract(tt.args.surface_normal, tt.args.eta)
+			if gotOk != tt.wantOk {
+				t.Fatalf("Vector.Refract() ok = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotOk && !gotRefracted_vector.CloseTo(tt.wantRefracted_vector) {
+				t.Errorf("Vector.Refract() = %v, want %v", gotRefracted_vector, tt.wantRefracted_vector)
+			}
+		})
+	}
+}
+
+func TestVector_Cross(t *testing.T) {
+	type fields struct {
+		X float64
+		Y float64
+		Z float64
+	}
+	type args struct {
+		u *Vector
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   *Vector
+	}{
+		{
+			name:   "X cross Y",
+			fields: fields{1.0, 0.0, 0.0},
+			args:   args{u: &Vector{0.0, 1.0, 0.0}},
+			want:   &Vector{0.0, 0.0, 1.0},
+		},
+		{
+			name:   "Y cross X",
+			fields: fields{0.0, 1.0, 0.0},
+			args:   args{u: &Vector{1.0, 0.0, 0.0}},
+			want:   &Vector{0.0, 0.0, -1.0},
+		},
+		{
+			name:   "Parallel vectors",
+			fields: fields{1.0, 2.0, 3.0},
+			args:   args{u: &Vector{2.0, 4.0, 6.0}},
+			want:   &Vector{0.0, 0.0, 0.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Vector{
+				X: tt.fields.X,
+				Y: tt.fields.Y,
+				Z: tt.fields.Z,
+			}
+			if got := v.Cross(tt.args.u); !got.CloseTo(tt.want) {
+				t.Errorf("Vector.Cross() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,84 +1,76 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"image"
 	"image/color"
-	"image/png"
+	"image/draw"
 	"log"
-	"os"
 
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/cameras"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/lights"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/materials"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/meshfiles"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/objects"
-	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/rays"
+	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/outputs"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/scenes"
 	"github.com/HughBlayney/go-ray-tracing/internal/pkgs/vectors"
 )
 
-const frame_height float64 = 1.0
-const frame_width float64 = 1.0
+var projection = flag.String("projection", "perspective", "One of perspective, isometric, fisheye, equirectangular, cubemap, side-by-side, anaglyph or ods")
+var interocular = flag.Float64("interocular", 2.0, "Distance between the eyes for the stereo projections")
 
-var viewer_vector *vectors.Vector = &vectors.Vector{
-	X: 0.0,
-	Y: 0.0,
-	Z: -10.0,
-}
-
-var top_left *vectors.Vector = &vectors.Vector{
-	X: -frame_width / 2.0,
-	Y: frame_height / 2.0,
-	Z: 0.0,
-}
+var obj_path = flag.String("obj", "", "Wavefront OBJ file to add to the scene")
+var ply_path = flag.String("ply", "", "Stanford PLY file to add to the scene")
+var stl_path = flag.String("stl", "", "STL file to add to the scene")
+var smooth_stl = flag.Bool("smooth-stl", false, "Give STL meshes smooth normals instead of their facet normals")
+var heightmap_path = flag.String("heightmap", "", "Grayscale PNG heightmap to lay across the floor as terrain")
+var heightmap_scale = flag.Float64("heightmap-scale", 5.0, "Height of white in the heightmap, in scene units")
+var accelerator = flag.String("accelerator", "bvh", "One of bvh, grid or kdtree")
+var accelerator_cache = flag.String("accelerator-cache", "", "Directory to keep meshes' BVHs and the scene's accelerator in between runs, so they're only built once")
+var depth = flag.Int("depth", 5, "How many reflections to follow")
+var crop = flag.String("crop", "", "Only render the pixels inside x0,y0,x1,y1, e.g. 800,400,1200,700")
+var canvas = flag.Bool("canvas", false, "With -crop, write the full frame with only the cropped region filled in")
 
-const height_res int = 100
-const width_res int = 200
-
-func subdivide(start float64, end float64, num_segments int) []float64 {
-	if end == start {
-		log.Fatal("Start and end values must be different.")
-	} else if end < start {
-		log.Fatal("Start value must be less than end value.")
+func cropRegion(camera cameras.Camera) image.Rectangle {
+	width, height := camera.Resolution()
+	frame := image.Rect(0, 0, width, height)
+	if *crop == "" {
+		return frame
 	}
-	sub_dist := (end - start) / float64(num_segments)
-
-	segments := make([]float64, num_segments)
-
-	current_value := start
+	var x0, y0, x1, y1 int
+	_, err := fmt.Sscanf(*crop, "%d,%d,%d,%d", &x0, &y0, &x1, &y1)
+	if err != nil {
+		log.Fatalf("Can't read crop region %q: %v", *crop, err)
+	}
+	region := image.Rect(x0, y0, x1, y1).Intersect(frame)
+	if region.Empty() {
+		log.Fatal("Crop region must overlap the image.")
+	}
+	return region
+}
 
-	for i := range segments {
-		segments[i] = current_value
-		current_value += sub_dist
+func newImage(camera cameras.Camera) *image.RGBA {
+	// Just the crop region, or with -canvas the full frame, left transparent
+	// outside the crop.
+	if !*canvas {
+		return image.NewRGBA(cropRegion(camera))
 	}
+	width, height := camera.Resolution()
+	return image.NewRGBA(image.Rect(0, 0, width, height))
+}
 
-	return segments
+func render(scene scenes.Scene, camera cameras.Camera, img draw.Image) {
+	scene.RenderRegion(img, cropRegion(camera), cameras.Samples(camera), cameras.RayGenerator(camera))
 }
 
 func main() {
+	flag.Parse()
+
 	width := 2000
 	height := 1000
 
-	upLeft := image.Point{0, 0}
-	lowRight := image.Point{width, height}
-
-	img := image.NewRGBA(image.Rectangle{upLeft, lowRight})
-
-	// As an initial test, we'll define the screen as a plane at 0, 0, 1
-	xs := subdivide(-2.0, 2.0, width)
-	ys := subdivide(-1.0, 1.0, height)
-	z := 1.0
-	// We need to reverse the ys to go top-down
-	for i := len(ys)/2 - 1; i >= 0; i-- {
-		opp := len(ys) - 1 - i
-		ys[i], ys[opp] = ys[opp], ys[i]
-	}
-	var screen_vectors [][]*vectors.Vector
-	for _, y := range ys {
-		var screen_row []*vectors.Vector
-		for _, x := range xs {
-			screen_row = append(screen_row, &vectors.Vector{X: x, Y: y, Z: z})
-		}
-		screen_vectors = append(screen_vectors, screen_row)
-	}
 	// Colors are defined by Red, Green, Blue, Alpha uint8 values.
 	cyan := color.RGBA{100, 200, 200, 0xff}
 	cyanmat := materials.MakeMaterial(
@@ -148,36 +140,133 @@ func main() {
 		Material:    greenmat,
 	}
 
-	screen_rays := rays.FireRays(screen_vectors, viewer_vector)
-
 	scene := scenes.Scene{
 		Objects: []objects.Object{sphere, sphere2, plane1, plane2, plane3, plane4},
 		Lights: []lights.Light{{
 			Color:     color.RGBA{0xff, 0xff, 0xff, 0xff},
 			Intensity: 1.0,
-			Position:  vectors.Vector{15.0, 30.0, 30.0},
+			Position:  vectors.Vector{X: 15.0, Y: 30.0, Z: 30.0},
 		}},
 		AmbientColour: color.RGBA{100, 100, 100, 0xff},
+		MaxDepth:      *depth,
 	}
 
-	colour_matrix := scene.Render(screen_rays)
+	var loader meshfiles.Loader
+	if *accelerator_cache != "" {
+		loader.Cache = &objects.AcceleratorCache{Dir: *accelerator_cache}
+	}
+	if *obj_path != "" {
+		model, err := loader.LoadOBJ(*obj_path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scene.Objects = append(scene.Objects, model.Objects()...)
+	}
+	if *ply_path != "" {
+		model, err := loader.LoadPLY(*ply_path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scene.Objects = append(scene.Objects, model.Objects()...)
+	}
+	if *stl_path != "" {
+		model, err := loader.LoadSTL(*stl_path, *smooth_stl)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scene.Objects = append(scene.Objects, model.Objects()...)
+	}
+	if *heightmap_path != "" {
+		heights, err := meshfiles.LoadHeightmap(*heightmap_path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Covering the floor between the walls
+		corner := vectors.Vector{X: -25.0, Y: -10.0, Z: 0.0}
+		scene.Objects = append(scene.Objects, objects.MakeHeightfield(heights, corner, 50.0, 200.0, *heightmap_scale, greymat))
+	}
 
-	// Set color for each pixel. Cyan if hits sphere, transparent otherwise.
-	for i, row := range colour_matrix {
-		for j, color := range row {
-			img.Set(j, i, color)
+	switch *accelerator {
+	case "bvh":
+		scene.Accelerator = objects.BVHAccelerator
+	case "grid":
+		scene.Accelerator = objects.GridAccelerator
+	case "kdtree":
+		scene.Accelerator = objects.KDTreeAccelerator
+	default:
+		log.Fatalf("Unknown accelerator %q.", *accelerator)
+	}
+	if loader.Cache != nil {
+		var err error
+		if scene, err = scene.AcceleratedCached(*loader.Cache); err != nil {
+			log.Fatal(err)
 		}
 	}
 
-	f, err := os.Create("./images/output.png")
-	if err != nil {
-		log.Fatal(err)
+	// Centre of the room, for the panoramic projections
+	room_centre := vectors.Vector{X: 0.0, Y: 0.0, Z: 100.0}
+	forward := vectors.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	up := vectors.Vector{X: 0.0, Y: 1.0, Z: 0.0}
+
+	// Roughly the framing of the old hand-built 4x2 screen, 11 units in front of the viewer
+	viewer := vectors.Vector{X: 0.0, Y: 0.0, Z: -10.0}
+	look_at := vectors.Vector{X: 0.0, Y: 0.0, Z: 125.0}
+	vfov := 10.4
+
+	var camera cameras.Camera
+	switch *projection {
+	case "perspective":
+		camera = cameras.MakePerspective(viewer, look_at, up, vfov, width, height)
+	case "side-by-side":
+		left, right := cameras.MakeStereo(viewer, look_at, up, vfov, width/2, height, *interocular, 135.0)
+		camera = cameras.SideBySide{Left: left, Right: right}
+	case "anaglyph":
+		left, right := cameras.MakeStereo(viewer, look_at, up, vfov, width, height, *interocular, 135.0)
+		img := newImage(left)
+		render(scene, left, outputs.AnaglyphEye{RGBA: img, Left: true})
+		render(scene, right, outputs.AnaglyphEye{RGBA: img})
+		err := outputs.SavePNG("./images/output.png", img)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "ods":
+		left, right := cameras.MakeOmniStereo(room_centre, *room_centre.Add(&forward), up, width, *interocular, 0.0)
+		camera = cameras.TopBottom{Top: left, Bottom: right}
+	case "isometric":
+		camera = cameras.MakeIsometric(vectors.Vector{X: 0.0, Y: -5.0, Z: 125.0}, 20.0, 30.0, width, height)
+	case "fisheye":
+		camera = cameras.MakeFisheye(
+			room_centre,
+			*room_centre.Add(&forward),
+			up,
+			cameras.Equisolid,
+			cameras.FisheyeIntrinsics(cameras.Equisolid, 180.0, width, height),
+			width,
+			height,
+		)
+	case "equirectangular":
+		camera = cameras.MakeEquirectangular(room_centre, *room_centre.Add(&forward), up, width)
+	case "cubemap":
+		var faces [6]image.Image
+		for i, face := range cameras.MakeCubemap(room_centre, height) {
+			img := newImage(face)
+			render(scene, face, img)
+			faces[i] = img
+		}
+		err := outputs.SaveCubemap("./images", "output", cameras.CubemapFaceNames, faces)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	default:
+		log.Fatalf("Unknown projection %q.", *projection)
 	}
-	defer f.Close()
 
-	// Encode to `PNG` with `DefaultCompression` level
-	// then save to file
-	err = png.Encode(f, img)
+	img := newImage(camera)
+	render(scene, camera, img)
+
+	err := outputs.SavePNG("./images/output.png", img)
 	if err != nil {
 		log.Fatal(err)
 	}